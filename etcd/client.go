@@ -0,0 +1,311 @@
+// Package etcd 提供基于etcd v3的配置源，接口与语义与 nacos.NacosClient 保持一致
+//
+// dataId/group 映射为键 <prefix>/<namespace>/<group>/<dataId>，监听基于etcd watch，
+// 配置被删除时向监听回调推送空字符串。Client 实现了 nacos.ConfigSource。
+package etcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// watchRetryInterval watch中断后重新建立的间隔
+const watchRetryInterval = time.Second
+
+// Client etcd配置源客户端
+type Client struct {
+	cli    *clientv3.Client
+	owned  bool
+	config Config
+	logger nacos.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ nacos.ConfigSource = (*Client)(nil)
+
+// Option 客户端可选配置
+type Option func(*Client)
+
+// WithLogger 设置客户端日志，未设置时使用 nacos.DefaultLogger()
+func WithLogger(logger nacos.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewClient 根据配置连接etcd并创建客户端
+func NewClient(config Config, opts ...Option) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("配置验证失败: %w", err)
+	}
+	config = config.withDefaults()
+
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Etcd.Endpoints,
+		Username:    config.Etcd.Username,
+		Password:    config.Etcd.Password,
+		DialTimeout: config.Etcd.DialTimeout,
+	})
+	if err != nil {
+		return nil, nacos.NewNacosError(nacos.ErrClientInitFailed.Code, "创建etcd客户端失败", err)
+	}
+
+	c := newClient(cli, config, opts...)
+	c.owned = true
+	return c, nil
+}
+
+// NewClientFromEtcd 使用已有的etcd客户端创建配置源，Close 时不会关闭传入的客户端
+func NewClientFromEtcd(cli *clientv3.Client, config Config, opts ...Option) (*Client, error) {
+	if cli == nil {
+		return nil, nacos.NewNacosError(nacos.ErrClientNotInit.Code, "etcd客户端不能为空", nil)
+	}
+	if len(config.Etcd.Endpoints) == 0 {
+		config.Etcd.Endpoints = cli.Endpoints()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("配置验证失败: %w", err)
+	}
+	return newClient(cli, config.withDefaults(), opts...), nil
+}
+
+func newClient(cli *clientv3.Client, config Config, opts ...Option) *Client {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{
+		cli:    cli,
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) log() nacos.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return nacos.DefaultLogger()
+}
+
+// resolve 补全默认的 dataId/group
+func (c *Client) resolve(dataId, group string) (string, string) {
+	if dataId == "" {
+		dataId = c.config.Etcd.Dataid
+	}
+	if group == "" {
+		group = c.config.Etcd.Group
+	}
+	return dataId, group
+}
+
+// Key 返回 dataId/group 对应的etcd键
+func (c *Client) Key(dataId, group string) string {
+	dataId, group = c.resolve(dataId, group)
+	return path.Join(c.config.Etcd.Prefix, c.config.Etcd.Namespace, group, dataId)
+}
+
+// key 校验名称并返回etcd键
+func (c *Client) key(dataId, group string) (string, string, string, error) {
+	dataId, group = c.resolve(dataId, group)
+	if dataId == "" {
+		return "", "", "", nacos.NewNacosError(nacos.ErrConfigInvalid.Code, "dataId不能为空", nil)
+	}
+	for _, name := range []string{dataId, group} {
+		if strings.Contains(name, "/") || name == "." || name == ".." {
+			return "", "", "", nacos.NewNacosError(nacos.ErrConfigInvalid.Code, fmt.Sprintf("无效的配置名称: %s", name), nil)
+		}
+	}
+	return dataId, group, c.Key(dataId, group), nil
+}
+
+// GetConfig 获取配置，配置不存在时返回空字符串
+func (c *Client) GetConfig(ctx context.Context, dataId, group string) (string, error) {
+	if c == nil || c.cli == nil {
+		return "", fmt.Errorf("etcd客户端未初始化")
+	}
+	dataId, group, key, err := c.key(dataId, group)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.cli.Get(ctx, key)
+	if err != nil {
+		return "", nacos.NewNacosError(nacos.ErrOperationFailed.Code, fmt.Sprintf("获取配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+	}
+	return value(resp), nil
+}
+
+// PublishConfig 发布配置
+func (c *Client) PublishConfig(ctx context.Context, dataId, group, content string) error {
+	if c == nil || c.cli == nil {
+		return fmt.Errorf("etcd客户端未初始化")
+	}
+	dataId, group, key, err := c.key(dataId, group)
+	if err != nil {
+		return err
+	}
+	if content == "" {
+		return nacos.NewNacosError(nacos.ErrPublishFailed.Code, fmt.Sprintf("发布配置失败 [DataId: %s, Group: %s]", dataId, group), errors.New("配置内容不能为空"))
+	}
+
+	if _, err := c.cli.Put(ctx, key, content); err != nil {
+		return nacos.NewNacosError(nacos.ErrPublishFailed.Code, fmt.Sprintf("发布配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+	}
+	return nil
+}
+
+// DeleteConfig 删除配置，配置不存在时不报错
+func (c *Client) DeleteConfig(ctx context.Context, dataId, group string) error {
+	if c == nil || c.cli == nil {
+		return fmt.Errorf("etcd客户端未初始化")
+	}
+	dataId, group, key, err := c.key(dataId, group)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.cli.Delete(ctx, key); err != nil {
+		return nacos.NewNacosError(nacos.ErrDeleteFailed.Code, fmt.Sprintf("删除配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+	}
+	return nil
+}
+
+// ListenConfig 监听配置变化，监听持续到客户端关闭
+func (c *Client) ListenConfig(ctx context.Context, dataId, group string, callback func(string)) error {
+	_, err := c.Watch(ctx, dataId, group, callback)
+	return err
+}
+
+// Get 读取配置，等同于 GetConfig
+func (c *Client) Get(ctx context.Context, dataId, group string) (string, error) {
+	return c.GetConfig(ctx, dataId, group)
+}
+
+// Publish 发布配置，等同于 PublishConfig
+func (c *Client) Publish(ctx context.Context, dataId, group, content string) error {
+	return c.PublishConfig(ctx, dataId, group, content)
+}
+
+// Watch 监听配置变化，返回取消该监听的函数
+// 返回前会记录当前revision和内容，之后的变化都不会丢失；watch中断后从上次收到的revision继续，
+// 历史版本被压缩时重新读取配置，内容与上次推送不同才回调
+func (c *Client) Watch(ctx context.Context, dataId, group string, callback func(string)) (func(), error) {
+	if c == nil || c.cli == nil {
+		return nil, fmt.Errorf("etcd客户端未初始化")
+	}
+	dataId, group, key, err := c.key(dataId, group)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.cli.Get(ctx, key)
+	if err != nil {
+		return nil, nacos.NewNacosError(nacos.ErrListenFailed.Code, fmt.Sprintf("监听配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+	}
+	if callback == nil {
+		return func() {}, nil
+	}
+
+	watchCtx, cancel := context.WithCancel(c.ctx)
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.watch(watchCtx, dataId, group, key, resp.Header.Revision, value(resp), callback)
+	}()
+	return cancel, nil
+}
+
+// watch 持续监听键的变化，watch中断后重新建立，rev 为已处理到的revision，last 为最后推送的内容
+func (c *Client) watch(ctx context.Context, dataId, group, key string, rev int64, last string, callback func(string)) {
+	for {
+		rev, last = c.watchOnce(ctx, dataId, group, key, rev, last, callback)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+// watchOnce 建立一次watch并分发事件，返回最后处理的revision和推送的内容
+func (c *Client) watchOnce(ctx context.Context, dataId, group, key string, rev int64, last string, callback func(string)) (int64, string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for resp := range c.cli.Watch(clientv3.WithRequireLeader(ctx), key, clientv3.WithRev(rev+1)) {
+		if resp.CompactRevision != 0 {
+			// 历史版本已被压缩，中间的变化无法重放，重新读取当前内容后继续
+			c.log().Warn("etcd watch revision已被压缩",
+				nacos.LogKeyGroup, group, nacos.LogKeyDataId, dataId, "compactRevision", resp.CompactRevision)
+			return c.resync(ctx, dataId, group, key, rev, last, callback)
+		}
+		if err := resp.Err(); err != nil {
+			c.log().Warn("etcd watch中断",
+				nacos.LogKeyGroup, group, nacos.LogKeyDataId, dataId, nacos.LogKeyError, err.Error())
+			return rev, last
+		}
+		for _, ev := range resp.Events {
+			rev = ev.Kv.ModRevision
+			switch ev.Type {
+			case mvccpb.PUT:
+				last = string(ev.Kv.Value)
+			case mvccpb.DELETE:
+				last = ""
+			}
+			callback(last)
+		}
+	}
+	return rev, last
+}
+
+// resync 重新读取键的当前内容，与 last 不同时回调，返回读取时的revision；读取失败时保持原状等待重试
+func (c *Client) resync(ctx context.Context, dataId, group, key string, rev int64, last string, callback func(string)) (int64, string) {
+	resp, err := c.cli.Get(ctx, key)
+	if err != nil {
+		c.log().Warn("etcd重新读取配置失败",
+			nacos.LogKeyGroup, group, nacos.LogKeyDataId, dataId, nacos.LogKeyError, err.Error())
+		return rev, last
+	}
+	if content := value(resp); content != last {
+		last = content
+		callback(last)
+	}
+	return resp.Header.Revision, last
+}
+
+// value 返回Get结果中的配置内容，键不存在时为空字符串
+func value(resp *clientv3.GetResponse) string {
+	if len(resp.Kvs) == 0 {
+		return ""
+	}
+	return string(resp.Kvs[0].Value)
+}
+
+// Close 停止全部监听，由 NewClient 创建的etcd连接会一并关闭
+func (c *Client) Close() error {
+	if c == nil || c.cli == nil {
+		return nil
+	}
+	c.cancel()
+	c.wg.Wait()
+	if c.owned {
+		return c.cli.Close()
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package etcd
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// startEtcd 启动内嵌etcd，返回客户端地址
+func startEtcd(t *testing.T) string {
+	t.Helper()
+
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+
+	clientURL := url.URL{Scheme: "http", Host: freeAddr(t)}
+	peerURL := url.URL{Scheme: "http", Host: freeAddr(t)}
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatalf("StartEtcd() error = %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for embedded etcd")
+	}
+	return clientURL.Host
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func waitPush(t *testing.T, pushes <-chan string) string {
+	t.Helper()
+	select {
+	case content := <-pushes:
+		return content
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for push")
+		return ""
+	}
+}
+
+func TestClient(t *testing.T) {
+	endpoint := startEtcd(t)
+	client, err := NewClient(Config{Etcd: EtcdConfig{
+		Endpoints: []string{endpoint},
+		Namespace: "dev",
+		Dataid:    "app.yaml",
+	}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	if key := client.Key("", ""); key != "/config/dev/DEFAULT_GROUP/app.yaml" {
+		t.Errorf("Unexpected key %s", key)
+	}
+
+	if content, err := client.GetConfig(ctx, "app.yaml", ""); err != nil || content != "" {
+		t.Fatalf("GetConfig() of missing config = %q, %v", content, err)
+	}
+
+	pushes := make(chan string, 10)
+	if err := client.ListenConfig(ctx, "app.yaml", "", func(content string) { pushes <- content }); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+	// 取消后的监听不再收到推送
+	cancel, err := client.Watch(ctx, "app.yaml", "", func(content string) {
+		t.Errorf("Unexpected push to cancelled watch: %q", content)
+	})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	cancel()
+
+	if err := client.PublishConfig(ctx, "", "", "port: 8080"); err != nil {
+		t.Fatalf("PublishConfig() error = %v", err)
+	}
+	if got := waitPush(t, pushes); got != "port: 8080" {
+		t.Errorf("Expected push %q, got %q", "port: 8080", got)
+	}
+	if content, _ := client.GetConfig(ctx, "app.yaml", "DEFAULT_GROUP"); content != "port: 8080" {
+		t.Errorf("Expected published content, got %q", content)
+	}
+
+	if err := client.DeleteConfig(ctx, "app.yaml", ""); err != nil {
+		t.Fatalf("DeleteConfig() error = %v", err)
+	}
+	if got := waitPush(t, pushes); got != "" {
+		t.Errorf("Expected empty push after delete, got %q", got)
+	}
+
+	if err := client.PublishConfig(ctx, "app.yaml", "", ""); nacos.ErrorCode(err) != nacos.ErrPublishFailed.Code {
+		t.Errorf("Expected %s for empty content, got %v", nacos.ErrPublishFailed.Code, err)
+	}
+	if _, err := client.GetConfig(ctx, "a/b", ""); nacos.ErrorCode(err) != nacos.ErrConfigInvalid.Code {
+		t.Errorf("Expected %s for invalid dataId, got %v", nacos.ErrConfigInvalid.Code, err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  EtcdConfig
+		wantErr bool
+	}{
+		{"valid", EtcdConfig{Endpoints: []string{"127.0.0.1:2379"}}, false},
+		{"no endpoints", EtcdConfig{}, true},
+		{"relative prefix", EtcdConfig{Endpoints: []string{"127.0.0.1:2379"}, Prefix: "config"}, true},
+		{"slash in group", EtcdConfig{Endpoints: []string{"127.0.0.1:2379"}, Group: "a/b"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Etcd: tt.config}
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWatchCompacted(t *testing.T) {
+	endpoint := startEtcd(t)
+	client, err := NewClient(Config{Etcd: EtcdConfig{Endpoints: []string{endpoint}}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+	key := client.Key("app.yaml", "")
+
+	resp, err := client.cli.Put(ctx, key, "port: 1")
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	rev := resp.Header.Revision
+	// watch 中断期间错过的更新，且历史版本已被压缩
+	if _, err := client.cli.Put(ctx, key, "port: 2"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := client.cli.Put(ctx, key, "port: 3"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	// 压缩点之后没有该键的事件，只能通过重新读取得到最新内容
+	resp, err = client.cli.Put(ctx, client.Key("other.yaml", ""), "other")
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := client.cli.Compact(ctx, resp.Header.Revision); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+
+	pushes := make(chan string, 10)
+	watchCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.watch(watchCtx, "app.yaml", nacos.DefaultGroup, key, rev, "port: 1", func(content string) { pushes <- content })
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if got := waitPush(t, pushes); got != "port: 3" {
+		t.Errorf("Expected current content after compaction, got %q", got)
+	}
+	if err := client.PublishConfig(ctx, "app.yaml", "", "port: 4"); err != nil {
+		t.Fatalf("PublishConfig() error = %v", err)
+	}
+	if got := waitPush(t, pushes); got != "port: 4" {
+		t.Errorf("Expected push after resync, got %q", got)
+	}
+}
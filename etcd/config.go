@@ -0,0 +1,85 @@
+package etcd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Config etcd配置源配置
+type Config struct {
+	Etcd EtcdConfig `mapstructure:"etcd"`
+}
+
+// EtcdConfig etcd具体配置
+type EtcdConfig struct {
+	Endpoints   []string      `mapstructure:"endpoints"`
+	Username    string        `mapstructure:"username"`
+	Password    string        `mapstructure:"password"`
+	DialTimeout time.Duration `mapstructure:"dial_timeout"`
+	// Prefix 键前缀，配置的键为 <prefix>/<namespace>/<group>/<dataId>
+	Prefix    string `mapstructure:"prefix"`
+	Namespace string `mapstructure:"namespace"`
+	// Dataid、Group 调用时未指定 dataId/group 使用的默认值，与Nacos配置含义一致
+	Dataid string `mapstructure:"dataid"`
+	Group  string `mapstructure:"group"`
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		Etcd: EtcdConfig{
+			Endpoints:   []string{"127.0.0.1:2379"},
+			DialTimeout: 5 * time.Second,
+			Prefix:      "/config",
+			Namespace:   "public",
+			Group:       "DEFAULT_GROUP",
+		},
+	}
+}
+
+// Validate 验证配置
+func (c *Config) Validate() error {
+	if len(c.Etcd.Endpoints) == 0 {
+		return fmt.Errorf("etcd地址不能为空")
+	}
+	for _, endpoint := range c.Etcd.Endpoints {
+		if strings.TrimSpace(endpoint) == "" {
+			return fmt.Errorf("etcd地址不能为空")
+		}
+	}
+
+	if c.Etcd.Prefix != "" && !strings.HasPrefix(c.Etcd.Prefix, "/") {
+		return fmt.Errorf("键前缀必须以/开头: %s", c.Etcd.Prefix)
+	}
+
+	for _, name := range []string{c.Etcd.Namespace, c.Etcd.Dataid, c.Etcd.Group} {
+		if strings.Contains(name, "/") {
+			return fmt.Errorf("namespace、dataid、group不能包含/: %s", name)
+		}
+	}
+
+	if c.Etcd.DialTimeout < 0 {
+		return fmt.Errorf("连接超时时间不能为负数")
+	}
+
+	return nil
+}
+
+// withDefaults 补全未设置的配置项
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig().Etcd
+	if c.Etcd.DialTimeout == 0 {
+		c.Etcd.DialTimeout = defaults.DialTimeout
+	}
+	if c.Etcd.Prefix == "" {
+		c.Etcd.Prefix = defaults.Prefix
+	}
+	if c.Etcd.Namespace == "" {
+		c.Etcd.Namespace = defaults.Namespace
+	}
+	if c.Etcd.Group == "" {
+		c.Etcd.Group = defaults.Group
+	}
+	return c
+}
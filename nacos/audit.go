@@ -0,0 +1,274 @@
+package nacos
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 审计记录的处理结果
+const (
+	AuditApplied  = "applied"
+	AuditRejected = "rejected"
+)
+
+// AuditConfig 配置推送审计日志，可写在配置文件的 audit 段
+type AuditConfig struct {
+	// File 审计日志文件路径，为空表示不记录
+	File string `mapstructure:"file"`
+	// MaxSizeMB 单个文件的最大大小，超过后轮转，默认100MB
+	MaxSizeMB int `mapstructure:"max_size_mb"`
+	// MaxBackups 保留的历史文件数，0表示全部保留
+	MaxBackups int `mapstructure:"max_backups"`
+	// MaxAgeDays 历史文件保留天数，0表示不按时间清理
+	MaxAgeDays int `mapstructure:"max_age_days"`
+	// Compress 是否gzip压缩历史文件
+	Compress bool `mapstructure:"compress"`
+}
+
+// AuditRecord 一次配置推送的审计记录
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Namespace string    `json:"namespace"`
+	Group     string    `json:"group"`
+	DataId    string    `json:"dataId"`
+	// MD5 推送的原始内容的md5，与Nacos控制台显示的一致
+	MD5 string `json:"md5"`
+	// Status applied 或 rejected（解密、占位符解析或校验失败）
+	Status string `json:"status"`
+	// Reason 被拒绝的原因
+	Reason string `json:"reason,omitempty"`
+	// Diff 与本实例上次收到的原始内容的统一格式diff
+	Diff string `json:"diff,omitempty"`
+}
+
+// AuditLog 将配置推送以JSON行写入按大小轮转的本地文件
+type AuditLog struct {
+	writer *lumberjack.Logger
+
+	mu sync.Mutex
+	// last 每个配置上次收到的原始内容，用于生成diff
+	last map[string]string
+}
+
+// NewAuditLog 创建审计日志
+func NewAuditLog(config AuditConfig) (*AuditLog, error) {
+	if config.File == "" {
+		return nil, fmt.Errorf("审计日志文件路径不能为空")
+	}
+	if err := os.MkdirAll(filepath.Dir(config.File), 0o755); err != nil {
+		return nil, fmt.Errorf("创建审计日志目录失败: %w", err)
+	}
+
+	return &AuditLog{
+		writer: &lumberjack.Logger{
+			Filename:   config.File,
+			MaxSize:    config.MaxSizeMB,
+			MaxBackups: config.MaxBackups,
+			MaxAge:     config.MaxAgeDays,
+			Compress:   config.Compress,
+			LocalTime:  true,
+		},
+		last: make(map[string]string),
+	}, nil
+}
+
+// WithAuditLog 记录通过 ListenConfig 收到的每次配置推送
+// 未设置时若配置文件的 audit.file 不为空，客户端会自行创建并在 Close 时关闭
+func WithAuditLog(audit *AuditLog) Option {
+	return func(c *NacosClient) {
+		c.audit = audit
+	}
+}
+
+// record 写入一条审计记录，并更新该配置上次收到的内容
+func (a *AuditLog) record(namespace, dataId, group, content string, reason error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := group + "@@" + dataId
+	name := group + "/" + dataId
+	sum := md5.Sum([]byte(content))
+	rec := AuditRecord{
+		Time:      time.Now(),
+		Namespace: namespace,
+		Group:     group,
+		DataId:    dataId,
+		MD5:       hex.EncodeToString(sum[:]),
+		Status:    AuditApplied,
+		Diff:      unifiedDiff("a/"+name, "b/"+name, a.last[key], content),
+	}
+	if reason != nil {
+		rec.Status = AuditRejected
+		rec.Reason = reason.Error()
+	}
+	a.last[key] = content
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = a.writer.Write(append(line, '\n'))
+	return err
+}
+
+// seed 设置配置的初始内容，作为第一次推送diff的基准
+func (a *AuditLog) seed(dataId, group, content string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := group + "@@" + dataId
+	if _, ok := a.last[key]; !ok {
+		a.last[key] = content
+	}
+}
+
+// Query 查询审计记录，见 ReadAudit
+func (a *AuditLog) Query(query AuditQuery) ([]AuditRecord, error) {
+	return ReadAudit(a.writer.Filename, query)
+}
+
+// Close 关闭审计日志文件
+func (a *AuditLog) Close() error {
+	return a.writer.Close()
+}
+
+// AuditQuery 审计记录的查询条件，零值字段不参与过滤
+type AuditQuery struct {
+	Namespace string
+	Group     string
+	DataId    string
+	Status    string
+	Since     time.Time
+	Until     time.Time
+	// Limit 只返回最近的 Limit 条，0表示不限制
+	Limit int
+}
+
+func (q AuditQuery) match(rec AuditRecord) bool {
+	return (q.Namespace == "" || rec.Namespace == q.Namespace) &&
+		(q.Group == "" || rec.Group == q.Group) &&
+		(q.DataId == "" || rec.DataId == q.DataId) &&
+		(q.Status == "" || rec.Status == q.Status) &&
+		(q.Since.IsZero() || !rec.Time.Before(q.Since)) &&
+		(q.Until.IsZero() || rec.Time.Before(q.Until))
+}
+
+// ReadAudit 读取审计日志文件及其轮转后的历史文件，按时间顺序返回符合条件的记录
+func ReadAudit(file string, query AuditQuery) ([]AuditRecord, error) {
+	files, err := auditFiles(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []AuditRecord
+	for _, name := range files {
+		recs, err := readAuditFile(name, query)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, recs...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	if query.Limit > 0 && len(records) > query.Limit {
+		records = records[len(records)-query.Limit:]
+	}
+	return records, nil
+}
+
+// auditBackupTimeFormat lumberjack 历史文件名中的时间格式
+const auditBackupTimeFormat = "2006-01-02T15-04-05.000"
+
+// auditFiles 返回历史文件与当前文件，历史文件名为 <name>-<时间><ext>[.gz]
+func auditFiles(file string) ([]string, error) {
+	ext := filepath.Ext(file)
+	prefix := strings.TrimSuffix(file, ext) + "-"
+	backups, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, name := range backups {
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ext)
+		if _, err := time.Parse(auditBackupTimeFormat, strings.TrimPrefix(stamp, prefix)); err == nil {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+	if _, err := os.Stat(file); err == nil {
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func readAuditFile(name string, query AuditQuery) ([]AuditRecord, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("读取审计日志失败 [%s]: %w", name, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec AuditRecord
+		// 跳过写入中断产生的不完整行
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		if query.match(rec) {
+			records = append(records, rec)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取审计日志失败 [%s]: %w", name, err)
+	}
+	return records, nil
+}
+
+// auditPush 记录一次推送的投递结果，reason 为监听回调拒绝该推送的原因（解密、占位符解析或校验失败）
+func (c *NacosClient) auditPush(namespace, dataId, group, data string, reason error) {
+	if c.audit == nil {
+		return
+	}
+	if namespace == "" {
+		namespace = c.config.Nacos.Namespace
+	}
+	if err := c.audit.record(namespace, dataId, group, data, reason); err != nil {
+		c.clientLogger().Warn("写入审计日志失败", c.logFields(dataId, group, err)...)
+	}
+}
+
+// auditSeed 开始监听时读取当前内容作为diff基准
+func (c *NacosClient) auditSeed(dataId, group string) {
+	if c.audit == nil {
+		return
+	}
+	if content, err := c.getRawConfig(dataId, group); err == nil {
+		c.audit.seed(dataId, group, content)
+	}
+}
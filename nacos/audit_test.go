@@ -0,0 +1,133 @@
+package nacos
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+func TestAuditLog(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit", "nacos-audit.log")
+	audit, err := NewAuditLog(AuditConfig{File: file})
+	if err != nil {
+		t.Fatalf("NewAuditLog() error = %v", err)
+	}
+	defer audit.Close()
+
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("app.yaml", "DEFAULT_GROUP", "port: 8080\n")
+	client := newTestClient(sdk, WithAuditLog(audit))
+	validated := 0
+	client.RegisterValidator("app.yaml", "", ValidatorFunc(func(dataId, content string) error {
+		validated++
+		if strings.Contains(content, "port: 0") {
+			return errors.New("port must be positive")
+		}
+		return nil
+	}))
+
+	var received []string
+	if err := client.ListenConfig(context.Background(), "app.yaml", "", func(content string) {
+		received = append(received, content)
+	}); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: "port: 9090\n"})
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: "port: 0\n"})
+
+	records, err := audit.Query(AuditQuery{DataId: "app.yaml"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	applied := records[0]
+	sum := md5.Sum([]byte("port: 9090\n"))
+	if applied.Status != AuditApplied || applied.MD5 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected applied record: %+v", applied)
+	}
+	if !strings.Contains(applied.Diff, "-port: 8080\n+port: 9090\n") {
+		t.Errorf("Expected diff against the content at listen time, got:\n%s", applied.Diff)
+	}
+
+	rejected := records[1]
+	if rejected.Status != AuditRejected || !strings.Contains(rejected.Reason, "port must be positive") {
+		t.Errorf("Unexpected rejected record: %+v", rejected)
+	}
+	if len(received) != 1 {
+		t.Errorf("Expected rejected push not to reach callback, got %v", received)
+	}
+	if validated != 2 {
+		t.Errorf("Expected each push to be validated once on the delivery path, got %d", validated)
+	}
+
+	if records, _ := ReadAudit(file, AuditQuery{Status: AuditRejected}); len(records) != 1 {
+		t.Errorf("Expected 1 rejected record, got %d", len(records))
+	}
+	if records, _ := ReadAudit(file, AuditQuery{Since: time.Now().Add(time.Hour)}); len(records) != 0 {
+		t.Errorf("Expected no records in the future, got %d", len(records))
+	}
+	if records, _ := ReadAudit(file, AuditQuery{Limit: 1}); len(records) != 1 || records[0].Status != AuditRejected {
+		t.Errorf("Expected Limit to keep the latest record, got %+v", records)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	newText := "a\nb\nc\nd\nE\nf\ng\nh\ni\nj\nk\n"
+
+	want := "--- old\n+++ new\n" +
+		"@@ -2,9 +2,10 @@\n b\n c\n d\n-e\n+E\n f\n g\n h\n i\n j\n+k\n"
+	if got := unifiedDiff("old", "new", oldText, newText); got != want {
+		t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+	if got := unifiedDiff("old", "new", "", "x\n"); got != "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+x\n" {
+		t.Errorf("unifiedDiff() for new file =\n%s", got)
+	}
+	if got := unifiedDiff("old", "new", "same", "same"); got != "" {
+		t.Errorf("Expected empty diff for equal content, got %q", got)
+	}
+
+	// 大文件中的小改动只对变化部分建表
+	var big, changed strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&big, "line %d\n", i)
+		if i == 2500 {
+			changed.WriteString("changed\n")
+		} else {
+			fmt.Fprintf(&changed, "line %d\n", i)
+		}
+	}
+	if got := unifiedDiff("old", "new", big.String(), changed.String()); !strings.Contains(got, "@@ -2498,7 +2498,7 @@\n line 2497\n") || !strings.Contains(got, "-line 2500\n+changed\n") {
+		t.Errorf("Unexpected diff for a small change in a large file:\n%s", got)
+	}
+	if got := unifiedDiff("old", "new", "", big.String()); strings.Count(got, "\n+line ") != 5000 {
+		t.Errorf("Expected a new large file to be diffed without a table, got %d lines", strings.Count(got, "\n"))
+	}
+
+	// 变化范围过大时不建表
+	var rewritten strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&rewritten, "other %d\n", i)
+	}
+	allocs := testing.AllocsPerRun(1, func() {
+		if got := unifiedDiff("old", "new", big.String(), rewritten.String()); !strings.Contains(got, "变化范围过大") {
+			t.Errorf("Expected fallback header for a full rewrite, got %d bytes", len(got))
+		}
+	})
+	if allocs > 100 {
+		t.Errorf("Expected the fallback to avoid building a table, got %.0f allocations", allocs)
+	}
+}
@@ -0,0 +1,173 @@
+package nacos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacoshttp"
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// 访问Nacos的后端，通过 NacosConfig.Backend 选择
+const (
+	// BackendSDK 使用 nacos-sdk-go（默认）
+	BackendSDK = "sdk"
+	// BackendHTTP 使用只依赖标准库的 nacoshttp，通过 open API 和长轮询访问服务端
+	BackendHTTP = "http"
+)
+
+// newConfigClient 根据 config.Nacos.Backend 创建配置客户端
+func newConfigClient(config Config) (config_client.IConfigClient, error) {
+	switch strings.ToLower(config.Nacos.Backend) {
+	case "", BackendSDK:
+		return newSDKConfigClient(config)
+	case BackendHTTP:
+		return newHTTPConfigClient(config)
+	default:
+		return nil, fmt.Errorf("不支持的后端: %s", config.Nacos.Backend)
+	}
+}
+
+// httpConfigClient 将 nacoshttp.Client 适配为SDK的 IConfigClient
+type httpConfigClient struct {
+	client    *nacoshttp.Client
+	namespace string
+
+	mu sync.Mutex
+	// 与SDK一致，同一 dataId/group 只保留最后一个监听
+	cancels map[string]func()
+}
+
+var (
+	_ config_client.IConfigClient = (*httpConfigClient)(nil)
+	_ MetadataConfigClient        = (*httpConfigClient)(nil)
+)
+
+func newHTTPConfigClient(config Config) (*httpConfigClient, error) {
+	timeout := time.Duration(config.Nacos.TimeoutMs) * time.Millisecond
+	client, err := nacoshttp.New(nacoshttp.Config{
+		ServerAddr:  config.Nacos.Addr + ":" + strconv.FormatUint(config.Nacos.Port, 10),
+		ContextPath: config.Nacos.ContextPath,
+		Scheme:      config.Nacos.Scheme,
+		Namespace:   config.Nacos.Namespace,
+		Username:    config.Nacos.Username,
+		Password:    config.Nacos.Password,
+		Timeout:     timeout,
+		OnError: func(err error) {
+			packageLogger().Warn("长轮询监听配置失败", LogKeyNamespace, config.Nacos.Namespace, LogKeyError, err.Error())
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建Nacos HTTP客户端失败: %w", err)
+	}
+	return &httpConfigClient{client: client, namespace: config.Nacos.Namespace, cancels: make(map[string]func())}, nil
+}
+
+func (h *httpConfigClient) GetConfig(param vo.ConfigParam) (string, error) {
+	return h.client.GetConfig(context.Background(), param.DataId, param.Group)
+}
+
+func (h *httpConfigClient) PublishConfig(param vo.ConfigParam) (bool, error) {
+	err := h.client.Publish(context.Background(), nacoshttp.PublishParam{
+		DataId:  param.DataId,
+		Group:   param.Group,
+		Content: param.Content,
+		Type:    param.Type,
+		AppName: param.AppName,
+		Tags:    param.ConfigTags,
+		SrcUser: param.SrcUser,
+		CasMd5:  param.CasMd5,
+	})
+	return err == nil, err
+}
+
+func (h *httpConfigClient) PublishConfigWithMetadata(param nacoshttp.PublishParam) (bool, error) {
+	err := h.client.Publish(context.Background(), param)
+	return err == nil, err
+}
+
+func (h *httpConfigClient) GetConfigInfo(dataId, group string) (*nacoshttp.ConfigInfo, error) {
+	return h.client.GetConfigInfo(context.Background(), dataId, group)
+}
+
+func (h *httpConfigClient) DeleteConfig(param vo.ConfigParam) (bool, error) {
+	err := h.client.DeleteConfig(context.Background(), param.DataId, param.Group)
+	return err == nil, err
+}
+
+func (h *httpConfigClient) ListenConfig(param vo.ConfigParam) error {
+	onChange := param.OnChange
+	cancel, err := h.client.Listen(context.Background(), param.DataId, param.Group, func(content string) {
+		if onChange != nil {
+			onChange(h.namespace, param.Group, param.DataId, content)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	key := param.Group + "@@" + param.DataId
+	h.mu.Lock()
+	previous := h.cancels[key]
+	h.cancels[key] = cancel
+	h.mu.Unlock()
+	if previous != nil {
+		previous()
+	}
+	return nil
+}
+
+func (h *httpConfigClient) CancelListenConfig(param vo.ConfigParam) error {
+	key := param.Group + "@@" + param.DataId
+	h.mu.Lock()
+	cancel := h.cancels[key]
+	delete(h.cancels, key)
+	h.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
+func (h *httpConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	page, err := h.client.SearchConfig(context.Background(), nacoshttp.SearchParam{
+		Search:   param.Search,
+		DataId:   param.DataId,
+		Group:    param.Group,
+		AppName:  param.AppName,
+		Tags:     param.Tag,
+		PageNo:   param.PageNo,
+		PageSize: param.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ConfigPage{
+		TotalCount:     page.TotalCount,
+		PageNumber:     page.PageNumber,
+		PagesAvailable: page.PagesAvailable,
+	}
+	for _, item := range page.PageItems {
+		result.PageItems = append(result.PageItems, model.ConfigItem{
+			Id:      item.ID,
+			DataId:  item.DataId,
+			Group:   item.Group,
+			Content: item.Content,
+			Md5:     item.MD5,
+			Tenant:  item.Tenant,
+			Appname: item.AppName,
+		})
+	}
+	return result, nil
+}
+
+func (h *httpConfigClient) CloseClient() {
+	h.client.Close()
+}
@@ -0,0 +1,140 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacoshttp"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// newOpenAPIServer 只支持 public 命名空间的简易 v1 open API 服务端
+func newOpenAPIServer(t *testing.T) (host string, port uint64) {
+	var mu sync.Mutex
+	configs := make(map[string]string)
+	forms := make(map[string]url.Values)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/nacos/v1/cs/configs":
+			key := r.Form.Get("group") + "@@" + r.Form.Get("dataId")
+			mu.Lock()
+			defer mu.Unlock()
+			if r.Method == http.MethodPost {
+				configs[key] = r.PostForm.Get("content")
+				forms[key] = r.PostForm
+				w.Write([]byte("true"))
+				return
+			}
+			content, ok := configs[key]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			if r.Form.Get("show") == "all" {
+				form := forms[key]
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"dataId": form.Get("dataId"), "group": form.Get("group"), "content": content, "md5": nacoshttp.MD5(content),
+					"type": form.Get("type"), "appName": form.Get("appName"), "desc": form.Get("desc"),
+					"configTags": form.Get("config_tags"), "createUser": form.Get("src_user"),
+				})
+				return
+			}
+			w.Write([]byte(content))
+		case "/nacos/v1/cs/configs/listener":
+			deadline := time.Now().Add(500 * time.Millisecond)
+			for time.Now().Before(deadline) {
+				var changed []string
+				mu.Lock()
+				for _, line := range strings.Split(r.PostForm.Get("Listening-Configs"), "\x01") {
+					parts := strings.Split(line, "\x02")
+					if len(parts) >= 3 && nacoshttp.MD5(configs[parts[1]+"@@"+parts[0]]) != parts[2] {
+						changed = append(changed, parts[0]+"\x02"+parts[1]+"\x01")
+					}
+				}
+				mu.Unlock()
+				if len(changed) > 0 {
+					w.Write([]byte(url.QueryEscape(strings.Join(changed, ""))))
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), uint64(addr.Port)
+}
+
+func TestHTTPBackend(t *testing.T) {
+	host, port := newOpenAPIServer(t)
+	config := *DefaultConfig()
+	config.Nacos.Addr = host
+	config.Nacos.Port = port
+	config.Nacos.Dataid = "app.yaml"
+	config.Nacos.Backend = BackendHTTP
+
+	client, err := NewNacosClient(config)
+	if err != nil {
+		t.Fatalf("NewNacosClient() error = %v", err)
+	}
+	if _, ok := client.GetClient().(*httpConfigClient); !ok {
+		t.Fatalf("Expected http backend, got %T", client.GetClient())
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	if err := client.PublishConfig(ctx, "", "", "a: 1"); err != nil {
+		t.Fatalf("PublishConfig() error = %v", err)
+	}
+	if content, err := client.GetConfig(ctx, "", ""); err != nil || content != "a: 1" {
+		t.Errorf("GetConfig() = %q, %v", content, err)
+	}
+
+	pushes := make(chan string, 1)
+	if err := client.ListenConfig(ctx, "", "", func(content string) { pushes <- content }); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+	client.PublishConfig(ctx, "", "", "a: 2")
+	select {
+	case got := <-pushes:
+		if got != "a: 2" {
+			t.Errorf("Expected push a: 2, got %q", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for push through the http backend")
+	}
+
+	// 客户端自行创建的配置客户端在 Close 时关闭
+	backend := client.GetClient()
+	client.Close()
+	backend.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: DefaultGroup, Content: "a: 3"})
+	select {
+	case got := <-pushes:
+		t.Errorf("Expected long polling to stop after Close, got push %q", got)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestInvalidBackend(t *testing.T) {
+	config := *DefaultConfig()
+	config.Nacos.Addr = "127.0.0.1"
+	config.Nacos.Port = 8848
+	config.Nacos.Dataid = "app.yaml"
+	config.Nacos.Backend = "grpc"
+	if err := config.Validate(); err == nil {
+		t.Error("Expected unknown backend to fail validation")
+	}
+}
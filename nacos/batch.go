@@ -0,0 +1,140 @@
+package nacos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultBatchConcurrency GetConfigs 默认的并发数
+const defaultBatchConcurrency = 8
+
+// ConfigKey 配置的 dataId/group
+type ConfigKey struct {
+	DataId string
+	Group  string
+}
+
+func (k ConfigKey) String() string {
+	return k.Group + "/" + k.DataId
+}
+
+// ConfigResult 单个配置的获取结果
+type ConfigResult struct {
+	Key     ConfigKey
+	Content string
+	Err     error
+}
+
+// BatchError 批量获取时部分配置失败
+type BatchError struct {
+	Total  int
+	Failed []ConfigResult
+}
+
+func (e *BatchError) Error() string {
+	parts := make([]string, 0, len(e.Failed))
+	for _, r := range e.Failed {
+		parts = append(parts, fmt.Sprintf("%s: %v", r.Key, r.Err))
+	}
+	return fmt.Sprintf("批量获取配置失败 %d/%d: %s", len(e.Failed), e.Total, strings.Join(parts, "; "))
+}
+
+// Unwrap 返回各配置的错误，支持 errors.Is/errors.As
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, r := range e.Failed {
+		errs = append(errs, r.Err)
+	}
+	return errs
+}
+
+// Keys 返回失败的配置
+func (e *BatchError) Keys() []ConfigKey {
+	keys := make([]ConfigKey, 0, len(e.Failed))
+	for _, r := range e.Failed {
+		keys = append(keys, r.Key)
+	}
+	return keys
+}
+
+// WithBatchConcurrency 设置 GetConfigs 的最大并发数，默认8
+func WithBatchConcurrency(n int) Option {
+	return func(c *NacosClient) {
+		c.batchConcurrency = n
+	}
+}
+
+// GetConfigs 并发获取多个配置，结果与 keys 顺序一致
+// 同一配置的并发请求（包括不同的 GetConfigs 调用）只会请求一次；
+// 部分失败时返回全部结果以及列出失败配置的 *BatchError
+func (c *NacosClient) GetConfigs(ctx context.Context, keys []ConfigKey) ([]ConfigResult, error) {
+	if c == nil || c.client == nil {
+		return nil, fmt.Errorf("Nacos客户端未初始化")
+	}
+
+	concurrency := c.batchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make([]ConfigResult, len(keys))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, key := range keys {
+		if key.DataId == "" {
+			key.DataId = c.config.Nacos.Dataid
+		}
+		if key.Group == "" {
+			key.Group = c.config.Nacos.Group
+		}
+		results[i].Key = key
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, key ConfigKey) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Content, results[i].Err = c.getConfigShared(ctx, key)
+		}(i, key)
+	}
+	wg.Wait()
+
+	var failed []ConfigResult
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) > 0 {
+		return results, &BatchError{Total: len(keys), Failed: failed}
+	}
+	return results, nil
+}
+
+// getConfigShared 合并同一配置的并发请求
+// 合并后的请求不随单个调用方的ctx取消，调用方取消时直接返回
+func (c *NacosClient) getConfigShared(ctx context.Context, key ConfigKey) (string, error) {
+	shared := context.WithoutCancel(ctx)
+	ch := c.fetchGroup.DoChan(key.Group+"@@"+key.DataId, func() (interface{}, error) {
+		return c.GetConfig(shared, key.DataId, key.Group)
+	})
+
+	select {
+	case r := <-ch:
+		if r.Err != nil {
+			return "", r.Err
+		}
+		return r.Val.(string), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
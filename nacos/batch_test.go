@@ -0,0 +1,116 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// slowConfigClient 记录并发数与调用次数的SDK客户端
+type slowConfigClient struct {
+	*nacostest.ConfigClient
+	delay time.Duration
+	// failDataId 该dataId的请求返回错误
+	failDataId  string
+	inflight    int32
+	maxInflight int32
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (s *slowConfigClient) GetConfig(param vo.ConfigParam) (string, error) {
+	n := atomic.AddInt32(&s.inflight, 1)
+	defer atomic.AddInt32(&s.inflight, -1)
+	for {
+		max := atomic.LoadInt32(&s.maxInflight)
+		if n <= max || atomic.CompareAndSwapInt32(&s.maxInflight, max, n) {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	s.calls[param.DataId]++
+	s.mu.Unlock()
+
+	time.Sleep(s.delay)
+	if param.DataId == s.failDataId {
+		return "", errors.New("server error")
+	}
+	return s.ConfigClient.GetConfig(param)
+}
+
+func TestGetConfigs(t *testing.T) {
+	sdk := &slowConfigClient{ConfigClient: nacostest.NewConfigClient(), delay: 20 * time.Millisecond, failDataId: "broken.yaml", calls: make(map[string]int)}
+	var keys []ConfigKey
+	for i := 0; i < 12; i++ {
+		dataId := fmt.Sprintf("svc-%d.yaml", i)
+		sdk.Set(dataId, "DEFAULT_GROUP", dataId)
+		keys = append(keys, ConfigKey{DataId: dataId})
+	}
+	keys = append(keys, ConfigKey{DataId: "broken.yaml"}, ConfigKey{DataId: "svc-0.yaml", Group: "DEFAULT_GROUP"})
+
+	client := &NacosClient{
+		client:           sdk,
+		config:           &Config{Nacos: NacosConfig{Group: "DEFAULT_GROUP"}},
+		batchConcurrency: 4,
+	}
+
+	results, err := client.GetConfigs(context.Background(), keys)
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected *BatchError, got %v", err)
+	}
+	if len(batchErr.Failed) != 1 || batchErr.Keys()[0] != (ConfigKey{DataId: "broken.yaml", Group: "DEFAULT_GROUP"}) {
+		t.Errorf("Unexpected failed keys: %v", batchErr.Keys())
+	}
+	if ErrorCode(err) != ErrOperationFailed.Code {
+		t.Errorf("Expected combined error to carry %s, got %q", ErrOperationFailed.Code, ErrorCode(err))
+	}
+
+	if len(results) != len(keys) {
+		t.Fatalf("Expected %d results, got %d", len(keys), len(results))
+	}
+	for i := 0; i < 12; i++ {
+		if results[i].Content != keys[i].DataId || results[i].Err != nil {
+			t.Errorf("results[%d] = %+v", i, results[i])
+		}
+	}
+	if last := results[len(results)-1]; last.Content != "svc-0.yaml" {
+		t.Errorf("Expected duplicate key to be resolved, got %+v", last)
+	}
+
+	if max := atomic.LoadInt32(&sdk.maxInflight); max > 4 {
+		t.Errorf("Expected at most 4 concurrent requests, got %d", max)
+	}
+}
+
+func TestGetConfigsSingleflight(t *testing.T) {
+	sdk := &slowConfigClient{ConfigClient: nacostest.NewConfigClient(), delay: 50 * time.Millisecond, calls: make(map[string]int)}
+	sdk.Set("app.yaml", "DEFAULT_GROUP", "a: 1")
+	client := newTestClient(sdk)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetConfigs(context.Background(), []ConfigKey{{DataId: "app.yaml"}}); err != nil {
+				t.Errorf("GetConfigs() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := sdk.calls["app.yaml"]; calls >= 5 {
+		t.Errorf("Expected concurrent requests to be merged, got %d calls", calls)
+	}
+}
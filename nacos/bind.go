@@ -0,0 +1,323 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// BindTag 绑定配置使用的结构体标签，格式为 nacos:"dataId[,group]"
+const BindTag = "nacos"
+
+// Binding 结构体字段与配置的绑定，字段随配置推送实时更新
+//
+//	type AppConfig struct {
+//	    DB    DBConf    `nacos:"db.yaml,DEFAULT_GROUP"`
+//	    Redis RedisConf `nacos:"redis.yaml"`
+//	}
+//
+// 字段按 dataId 扩展名（yaml/json/toml/properties）解析，字段类型为 string 时直接赋值原始内容。
+// 推送更新字段时持有写锁，并发读取字段需通过 View
+type Binding struct {
+	client *NacosClient
+	target reflect.Value
+	fields []*boundField
+
+	mu sync.RWMutex
+	// ready 初次加载完成后为 true，之前到达的推送不调用 hook
+	ready bool
+
+	hooks      map[string][]func(old, new interface{})
+	validators map[string][]func(value interface{}) error
+
+	cancelMu sync.Mutex
+	cancels  []func()
+}
+
+// boundField 带 nacos 标签的字段
+type boundField struct {
+	name  string
+	index int
+	key   ConfigKey
+	// pushed 已由推送更新过，之后完成的初次读取结果已过期，持有 Binding.mu 访问
+	pushed bool
+}
+
+// BindOption 绑定的可选配置
+type BindOption func(*Binding)
+
+// BindOnChange 推送使字段值变化后调用 hook，old 和 new 为变化前后的字段值，初次加载（包括 Bind 返回前到达的推送）不调用
+func BindOnChange(field string, hook func(old, new interface{})) BindOption {
+	return func(b *Binding) {
+		b.hooks[field] = append(b.hooks[field], hook)
+	}
+}
+
+// BindValidator 字段赋值前校验解析后的值，校验失败时保持当前值
+// 字段类型（或其指针）实现了 Validate() error 时也会在赋值前调用
+func BindValidator(field string, validator func(value interface{}) error) BindOption {
+	return func(b *Binding) {
+		b.validators[field] = append(b.validators[field], validator)
+	}
+}
+
+// Bind 按 target 中字段的 nacos 标签加载配置并监听变化，target 必须是结构体指针
+//
+// 配置不存在时字段保持零值；初次加载时解析或校验失败返回 ErrConfigInvalid。
+// 推送的内容解析或校验失败、或配置被删除时，字段保持当前值
+func (c *NacosClient) Bind(ctx context.Context, target interface{}, opts ...BindOption) (*Binding, error) {
+	if c == nil || c.client == nil {
+		return nil, fmt.Errorf("Nacos客户端未初始化")
+	}
+
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("绑定目标必须是结构体指针，实际为 %T", target)
+	}
+
+	b := &Binding{
+		client:     c,
+		target:     v.Elem(),
+		hooks:      make(map[string][]func(old, new interface{})),
+		validators: make(map[string][]func(value interface{}) error),
+	}
+	if err := b.parseFields(); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if err := b.checkOptions(); err != nil {
+		return nil, err
+	}
+
+	// 先监听再读取，避免丢失两者之间的推送；字段在读取期间已被推送更新时丢弃读取结果
+	for _, f := range b.fields {
+		f := f
+		cancel, err := c.addListener(f.key.DataId, f.key.Group, func(content string) { b.onChange(f, content) })
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+		b.cancelMu.Lock()
+		b.cancels = append(b.cancels, cancel)
+		b.cancelMu.Unlock()
+	}
+
+	keys := make([]ConfigKey, len(b.fields))
+	for i, f := range b.fields {
+		keys[i] = f.key
+	}
+	results, err := c.GetConfigs(ctx, keys)
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+	for i, f := range b.fields {
+		if results[i].Content == "" {
+			c.clientLogger().Warn("绑定的配置不存在，字段保持零值",
+				LogKeyGroup, f.key.Group, LogKeyDataId, f.key.DataId, "field", f.name)
+			continue
+		}
+		if err := b.apply(f, results[i].Content, true); err != nil {
+			b.Close()
+			return nil, err
+		}
+	}
+	b.mu.Lock()
+	b.ready = true
+	b.mu.Unlock()
+	return b, nil
+}
+
+// parseFields 收集带 nacos 标签的字段
+func (b *Binding) parseFields() error {
+	t := b.target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(BindTag)
+		if !ok || tag == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("字段 %s 未导出，无法绑定", field.Name)
+		}
+
+		dataId, group, _ := strings.Cut(tag, ",")
+		key := ConfigKey{DataId: strings.TrimSpace(dataId), Group: strings.TrimSpace(group)}
+		if key.DataId == "" {
+			return fmt.Errorf("字段 %s 的 nacos 标签缺少 dataId", field.Name)
+		}
+		if key.Group == "" {
+			key.Group = b.client.config.Nacos.Group
+		}
+		b.fields = append(b.fields, &boundField{name: field.Name, index: i, key: key})
+	}
+	if len(b.fields) == 0 {
+		return fmt.Errorf("%s 没有带 nacos 标签的字段", t)
+	}
+	return nil
+}
+
+// checkOptions 检查 hook 和校验器引用的字段是否存在
+func (b *Binding) checkOptions() error {
+	bound := b.Fields()
+	for _, names := range [][]string{sortedNames(b.hooks), sortedNames(b.validators)} {
+		for _, name := range names {
+			if _, ok := bound[name]; !ok {
+				return fmt.Errorf("字段 %s 不存在或没有 nacos 标签", name)
+			}
+		}
+	}
+	return nil
+}
+
+// onChange 处理推送，失败时保持当前值
+func (b *Binding) onChange(f *boundField, content string) {
+	logger := b.client.clientLogger()
+	if content == "" {
+		logger.Warn("绑定的配置被删除，字段保持当前值",
+			LogKeyGroup, f.key.Group, LogKeyDataId, f.key.DataId, "field", f.name)
+		return
+	}
+	if err := b.apply(f, content, false); err != nil {
+		logger.Warn("绑定的配置更新失败，字段保持当前值",
+			append(b.client.logFields(f.key.DataId, f.key.Group, err), "field", f.name)...)
+	}
+}
+
+// apply 解析、校验并更新字段
+// initial 表示初次读取的结果，字段已被推送更新时丢弃；推送在初次加载完成后使值变化时调用 hook
+func (b *Binding) apply(f *boundField, content string, initial bool) error {
+	field := b.target.Field(f.index)
+	value, err := decodeBinding(f.key.DataId, content, field.Type())
+	if err != nil {
+		return NewNacosError(ErrConfigInvalid.Code,
+			fmt.Sprintf("解析绑定的配置失败 [DataId: %s, Group: %s, Field: %s]", f.key.DataId, f.key.Group, f.name), err)
+	}
+	if err := b.validate(f.name, value); err != nil {
+		return NewNacosError(ErrConfigInvalid.Code,
+			fmt.Sprintf("绑定的配置未通过校验 [DataId: %s, Group: %s, Field: %s]", f.key.DataId, f.key.Group, f.name), err)
+	}
+
+	b.mu.Lock()
+	if initial && f.pushed {
+		b.mu.Unlock()
+		return nil
+	}
+	if !initial {
+		f.pushed = true
+	}
+	notify := !initial && b.ready
+	old := field.Interface()
+	field.Set(value)
+	b.mu.Unlock()
+
+	if notify && !reflect.DeepEqual(old, value.Interface()) {
+		for _, hook := range b.hooks[f.name] {
+			hook(old, value.Interface())
+		}
+	}
+	return nil
+}
+
+// validate 执行字段的校验器以及类型自身的 Validate 方法
+func (b *Binding) validate(name string, value reflect.Value) error {
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	for _, candidate := range []reflect.Value{value, ptr} {
+		if v, ok := candidate.Interface().(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	for _, validator := range b.validators[name] {
+		if err := validator(value.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// View 持有读锁执行 fn，用于在推送更新期间一致地读取绑定的字段
+func (b *Binding) View(fn func()) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	fn()
+}
+
+// Fields 返回字段名与绑定的配置
+func (b *Binding) Fields() map[string]ConfigKey {
+	fields := make(map[string]ConfigKey, len(b.fields))
+	for _, f := range b.fields {
+		fields[f.name] = f.key
+	}
+	return fields
+}
+
+// Close 停止监听，字段保持当前值
+func (b *Binding) Close() {
+	b.cancelMu.Lock()
+	cancels := b.cancels
+	b.cancels = nil
+	b.cancelMu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// decodeBinding 按dataId扩展名将配置内容解析为 typ 类型的值
+func decodeBinding(dataId, content string, typ reflect.Type) (reflect.Value, error) {
+	ptr := reflect.New(typ)
+	if typ.Kind() == reflect.String {
+		ptr.Elem().SetString(content)
+		return ptr.Elem(), nil
+	}
+
+	var err error
+	switch configTypeOf(dataId) {
+	case "json":
+		err = json.Unmarshal([]byte(content), ptr.Interface())
+	case "toml":
+		err = toml.Unmarshal([]byte(content), ptr.Interface())
+	case "properties":
+		var props map[string]string
+		if props, err = parseProperties(content); err == nil {
+			err = propertiesNode(nestProperties(props)).Decode(ptr.Interface())
+		}
+	default:
+		err = yaml.Unmarshal([]byte(content), ptr.Interface())
+	}
+	return ptr.Elem(), err
+}
+
+// propertiesNode 将展开后的属性转为yaml节点，值按yaml规则推断类型，便于解析到数值、布尔字段
+func propertiesNode(value interface{}) *yaml.Node {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(value)}
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, propertiesNode(m[key]))
+	}
+	return node
+}
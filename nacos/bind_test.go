@@ -0,0 +1,164 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+type bindDBConf struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+func (c bindDBConf) Validate() error {
+	if c.Port <= 0 {
+		return errors.New("port must be positive")
+	}
+	return nil
+}
+
+type bindRedisConf struct {
+	Addr string `json:"addr"`
+	DB   int    `json:"db"`
+}
+
+type bindAppConfig struct {
+	DB      bindDBConf        `nacos:"db.yaml,DEFAULT_GROUP"`
+	Redis   bindRedisConf     `nacos:"redis.json,SHARED"`
+	Limits  map[string]int    `nacos:"limits.properties"`
+	Banner  string            `nacos:"banner.txt"`
+	Missing map[string]string `nacos:"missing.yaml"`
+	Name    string
+}
+
+func TestBind(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("db.yaml", "DEFAULT_GROUP", "host: 10.0.0.1\nport: 3306\n")
+	sdk.Set("redis.json", "SHARED", `{"addr": "10.0.0.2:6379", "db": 1}`)
+	sdk.Set("limits.properties", "DEFAULT_GROUP", "qps=100\nburst=20\n")
+	sdk.Set("banner.txt", "DEFAULT_GROUP", "hello")
+	sdk.Set("missing.yaml", "DEFAULT_GROUP", "")
+	client := newTestClient(sdk)
+
+	var cfg bindAppConfig
+	var changes []string
+	binding, err := client.Bind(context.Background(), &cfg,
+		BindOnChange("DB", func(old, new interface{}) {
+			changes = append(changes, old.(bindDBConf).Host+"->"+new.(bindDBConf).Host)
+		}),
+		BindValidator("Redis", func(value interface{}) error {
+			if value.(bindRedisConf).Addr == "" {
+				return errors.New("addr is required")
+			}
+			return nil
+		}))
+	if err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	defer binding.Close()
+
+	if cfg.DB != (bindDBConf{Host: "10.0.0.1", Port: 3306}) || cfg.Redis.DB != 1 || cfg.Limits["qps"] != 100 || cfg.Banner != "hello" {
+		t.Errorf("Unexpected initial config: %+v", cfg)
+	}
+	if cfg.Missing != nil {
+		t.Errorf("Expected missing config to keep zero value, got %v", cfg.Missing)
+	}
+
+	publish := func(dataId, group, content string) {
+		sdk.PublishConfig(vo.ConfigParam{DataId: dataId, Group: group, Content: content})
+	}
+
+	publish("db.yaml", "DEFAULT_GROUP", "host: 10.0.0.9\nport: 3306\n")
+	if cfg.DB.Host != "10.0.0.9" {
+		t.Errorf("Expected DB to be live-updated, got %+v", cfg.DB)
+	}
+
+	// 校验失败与删除都保持当前值
+	publish("db.yaml", "DEFAULT_GROUP", "host: 10.0.0.10\nport: 0\n")
+	publish("redis.json", "SHARED", `{"db": 2}`)
+	publish("db.yaml", "DEFAULT_GROUP", "")
+	if cfg.DB.Host != "10.0.0.9" || cfg.Redis.DB != 1 {
+		t.Errorf("Expected invalid pushes to be rejected, got %+v", cfg)
+	}
+
+	// 内容未变化时不调用 hook
+	publish("db.yaml", "DEFAULT_GROUP", "port: 3306\nhost: 10.0.0.9\n")
+	if len(changes) != 1 || changes[0] != "10.0.0.1->10.0.0.9" {
+		t.Errorf("Unexpected change hooks: %v", changes)
+	}
+
+	binding.Close()
+	publish("db.yaml", "DEFAULT_GROUP", "host: 10.0.0.11\nport: 3306\n")
+	if cfg.DB.Host != "10.0.0.9" {
+		t.Errorf("Expected no updates after Close, got %+v", cfg.DB)
+	}
+}
+
+// racingConfigClient 首次读取 dataId 时在返回前推送更新的内容，模拟读取期间到达的推送
+type racingConfigClient struct {
+	*nacostest.ConfigClient
+	dataId string
+	newer  string
+	once   sync.Once
+}
+
+func (c *racingConfigClient) GetConfig(param vo.ConfigParam) (string, error) {
+	content, err := c.ConfigClient.GetConfig(param)
+	if param.DataId == c.dataId {
+		c.once.Do(func() { c.Push(param.DataId, param.Group, c.newer) })
+	}
+	return content, err
+}
+
+func TestBindPushDuringInitialLoad(t *testing.T) {
+	sdk := &racingConfigClient{ConfigClient: nacostest.NewConfigClient(), dataId: "db.yaml", newer: "host: 10.0.0.9\nport: 3306\n"}
+	sdk.Set("db.yaml", "DEFAULT_GROUP", "host: 10.0.0.1\nport: 3306\n")
+	client := newTestClient(sdk)
+
+	var cfg struct {
+		DB bindDBConf `nacos:"db.yaml"`
+	}
+	hooked := 0
+	binding, err := client.Bind(context.Background(), &cfg, BindOnChange("DB", func(old, new interface{}) { hooked++ }))
+	if err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	defer binding.Close()
+
+	if cfg.DB.Host != "10.0.0.9" {
+		t.Errorf("Expected the push during the initial load not to be overwritten, got %+v", cfg.DB)
+	}
+	if hooked != 0 {
+		t.Errorf("Expected hooks not to run before Bind returns, got %d calls", hooked)
+	}
+
+	sdk.Push("db.yaml", "", "host: 10.0.0.10\nport: 3306\n")
+	if cfg.DB.Host != "10.0.0.10" || hooked != 1 {
+		t.Errorf("Expected later pushes to update and notify, got %+v after %d hooks", cfg.DB, hooked)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("db.yaml", "DEFAULT_GROUP", "host: [unclosed\n")
+	client := newTestClient(sdk)
+	ctx := context.Background()
+
+	var cfg struct {
+		DB bindDBConf `nacos:"db.yaml"`
+	}
+	if _, err := client.Bind(ctx, &cfg); ErrorCode(err) != ErrConfigInvalid.Code {
+		t.Errorf("Expected %s for invalid content, got %v", ErrConfigInvalid.Code, err)
+	}
+	if _, err := client.Bind(ctx, cfg); err == nil {
+		t.Error("Expected non-pointer target to fail")
+	}
+	if _, err := client.Bind(ctx, &cfg, BindOnChange("Redis", func(old, new interface{}) {})); err == nil {
+		t.Error("Expected hook on unknown field to fail")
+	}
+}
@@ -0,0 +1,226 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 启动加载的默认值
+const (
+	defaultBootstrapTimeout       = 30 * time.Second
+	defaultBootstrapRetryInterval = time.Second
+)
+
+// BootstrapConfig 启动时需要加载的配置，可写在配置文件的 bootstrap 段
+//
+//	bootstrap:
+//	  required: ["db.yaml", "SHARED/redis.yaml"]
+//	  optional: ["feature.yaml"]
+//	  timeout: 30s
+//	  degraded: false
+type BootstrapConfig struct {
+	// Required 必需的配置，格式为 dataId 或 GROUP/dataId
+	Required []string `mapstructure:"required"`
+	// Optional 可选的配置，获取失败不影响启动
+	Optional []string `mapstructure:"optional"`
+	// Timeout 等待必需配置的总超时时间，默认30秒
+	Timeout time.Duration `mapstructure:"timeout"`
+	// RetryInterval 重试间隔，默认1秒
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
+	// MaxRetries 每个必需配置的最大重试次数，0表示在超时前一直重试
+	MaxRetries int `mapstructure:"max_retries"`
+	// Degraded 为 true 时必需配置缺失也继续启动（降级模式），否则返回错误
+	Degraded bool `mapstructure:"degraded"`
+}
+
+// ParseConfigKey 解析 dataId 或 GROUP/dataId 形式的配置名
+func ParseConfigKey(s string) ConfigKey {
+	if group, dataId, ok := strings.Cut(s, "/"); ok {
+		return ConfigKey{DataId: dataId, Group: group}
+	}
+	return ConfigKey{DataId: s}
+}
+
+// Snapshot 启动时加载的配置快照
+type Snapshot struct {
+	configs      map[ConfigKey]string
+	defaultGroup string
+
+	// Missing 未能加载的配置及原因，包括可选配置
+	Missing []ConfigResult
+	// Degraded 是否缺少必需配置（降级模式）
+	Degraded bool
+	// LoadedAt 加载完成时间
+	LoadedAt time.Time
+}
+
+// Get 返回快照中的配置，group 为空时使用客户端的默认分组
+func (s *Snapshot) Get(dataId, group string) (string, bool) {
+	if group == "" {
+		group = s.defaultGroup
+	}
+	content, ok := s.configs[ConfigKey{DataId: dataId, Group: group}]
+	return content, ok
+}
+
+// Keys 返回快照中已加载的配置
+func (s *Snapshot) Keys() []ConfigKey {
+	keys := make([]ConfigKey, 0, len(s.configs))
+	for key := range s.configs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
+
+// BootstrapError 必需配置未能加载
+type BootstrapError struct {
+	Missing []ConfigResult
+}
+
+func (e *BootstrapError) Error() string {
+	parts := make([]string, 0, len(e.Missing))
+	for _, r := range e.Missing {
+		parts = append(parts, fmt.Sprintf("%s: %v", r.Key, r.Err))
+	}
+	return fmt.Sprintf("%d个必需配置未能加载: %s", len(e.Missing), strings.Join(parts, "; "))
+}
+
+// Unwrap 返回各配置的错误
+func (e *BootstrapError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Missing))
+	for _, r := range e.Missing {
+		errs = append(errs, r.Err)
+	}
+	return errs
+}
+
+// errConfigEmpty 配置不存在或内容为空
+var errConfigEmpty = NewNacosError(ErrConfigNotFound.Code, "配置不存在或内容为空", nil)
+
+// BootstrapNacos 加载配置文件、初始化客户端并按配置文件的 bootstrap 段加载配置
+func BootstrapNacos(ctx context.Context, configPath string, opts ...Option) (*NacosClient, *Snapshot, error) {
+	client, err := InitNacos(configPath, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot, err := client.Bootstrap(ctx, client.config.Bootstrap)
+	return client, snapshot, err
+}
+
+// Bootstrap 加载启动所需的配置并返回快照，阻塞直到必需配置全部加载、超时或重试次数用尽
+//
+// 内容为空视为配置不存在并继续重试；校验、解密和占位符错误重试无效，不再重试。
+// 必需配置缺失时，降级模式返回 Degraded 快照，否则返回快照和包含 *BootstrapError 的错误
+func (c *NacosClient) Bootstrap(ctx context.Context, config BootstrapConfig) (*Snapshot, error) {
+	if c == nil || c.client == nil {
+		return nil, fmt.Errorf("Nacos客户端未初始化")
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultBootstrapTimeout
+	}
+	interval := config.RetryInterval
+	if interval <= 0 {
+		interval = defaultBootstrapRetryInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	snapshot := &Snapshot{configs: make(map[ConfigKey]string), defaultGroup: c.config.Nacos.Group}
+	resolve := func(names []string) []ConfigKey {
+		keys := make([]ConfigKey, 0, len(names))
+		for _, name := range names {
+			key := ParseConfigKey(name)
+			if key.Group == "" {
+				key.Group = c.config.Nacos.Group
+			}
+			keys = append(keys, key)
+		}
+		return keys
+	}
+
+	// 可选配置只获取一次
+	optional := resolve(config.Optional)
+	results, _ := c.GetConfigs(ctx, optional)
+	for _, r := range results {
+		if r.Err == nil && r.Content == "" {
+			r.Err = errConfigEmpty
+		}
+		if r.Err != nil {
+			snapshot.Missing = append(snapshot.Missing, r)
+			continue
+		}
+		snapshot.configs[r.Key] = r.Content
+	}
+
+	missing := c.loadRequired(ctx, resolve(config.Required), snapshot, config.MaxRetries, interval)
+	snapshot.LoadedAt = time.Now()
+	snapshot.Missing = append(snapshot.Missing, missing...)
+	if len(missing) == 0 {
+		c.clientLogger().Info("启动配置加载完成", LogKeyNamespace, c.config.Nacos.Namespace, "loaded", len(snapshot.configs))
+		return snapshot, nil
+	}
+
+	bootErr := &BootstrapError{Missing: missing}
+	if config.Degraded {
+		snapshot.Degraded = true
+		c.clientLogger().Warn("必需配置未能加载，以降级模式启动",
+			LogKeyNamespace, c.config.Nacos.Namespace, LogKeyError, bootErr.Error())
+		return snapshot, nil
+	}
+	return snapshot, NewNacosError(ErrConfigLoadFailed.Code, "启动配置加载失败", bootErr)
+}
+
+// loadRequired 重试加载必需配置，返回最终仍缺失的配置
+func (c *NacosClient) loadRequired(ctx context.Context, keys []ConfigKey, snapshot *Snapshot, maxRetries int, interval time.Duration) []ConfigResult {
+	var permanent []ConfigResult
+	pending := keys
+
+	for attempt := 0; ; attempt++ {
+		results, _ := c.GetConfigs(ctx, pending)
+
+		var failed []ConfigResult
+		pending = pending[:0:0]
+		for _, r := range results {
+			if r.Err == nil && r.Content == "" {
+				r.Err = errConfigEmpty
+			}
+			switch {
+			case r.Err == nil:
+				snapshot.configs[r.Key] = r.Content
+			case isPermanentBootstrapError(r.Err):
+				permanent = append(permanent, r)
+			default:
+				failed = append(failed, r)
+				pending = append(pending, r.Key)
+			}
+		}
+
+		if len(failed) == 0 || (maxRetries > 0 && attempt >= maxRetries) {
+			return append(permanent, failed...)
+		}
+		c.clientLogger().Debug("必需配置未就绪，等待重试", "attempt", attempt+1, "pending", len(pending))
+
+		select {
+		case <-ctx.Done():
+			return append(permanent, failed...)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isPermanentBootstrapError 重试无法恢复的错误
+func isPermanentBootstrapError(err error) bool {
+	switch ErrorCode(err) {
+	case ErrConfigInvalid.Code, ErrDecryptFailed.Code, ErrPlaceholderUnresolved.Code:
+		return true
+	}
+	return errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,109 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+func TestBootstrap(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("db.yaml", "DEFAULT_GROUP", "host: 10.0.0.1")
+	sdk.Set("feature.yaml", "DEFAULT_GROUP", "beta: true")
+	client := newTestClient(sdk)
+
+	// redis.yaml 在第二次重试前发布
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		sdk.PublishConfig(vo.ConfigParam{DataId: "redis.yaml", Group: "SHARED", Content: "addr: 10.0.0.2"})
+	}()
+
+	snapshot, err := client.Bootstrap(context.Background(), BootstrapConfig{
+		Required:      []string{"db.yaml", "SHARED/redis.yaml"},
+		Optional:      []string{"feature.yaml", "missing.yaml"},
+		Timeout:       2 * time.Second,
+		RetryInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Bootstrap() error = %v", err)
+	}
+	if content, ok := snapshot.Get("redis.yaml", "SHARED"); !ok || content != "addr: 10.0.0.2" {
+		t.Errorf("Expected redis.yaml after retry, got %q", content)
+	}
+	if content, _ := snapshot.Get("db.yaml", ""); content != "host: 10.0.0.1" {
+		t.Errorf("Expected db.yaml in default group, got %q", content)
+	}
+	if snapshot.Degraded || len(snapshot.Missing) != 1 || snapshot.Missing[0].Key.DataId != "missing.yaml" {
+		t.Errorf("Expected only optional missing.yaml to be missing, got %+v", snapshot.Missing)
+	}
+	if len(snapshot.Keys()) != 3 {
+		t.Errorf("Expected 3 loaded configs, got %v", snapshot.Keys())
+	}
+}
+
+func TestBootstrapFailFast(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("db.yaml", "DEFAULT_GROUP", "host: 10.0.0.1")
+	sdk.Set("empty.yaml", "DEFAULT_GROUP", "")
+	client := newTestClient(sdk)
+
+	config := BootstrapConfig{
+		Required:      []string{"db.yaml", "empty.yaml", "missing.yaml"},
+		RetryInterval: 5 * time.Millisecond,
+		MaxRetries:    2,
+	}
+	snapshot, err := client.Bootstrap(context.Background(), config)
+
+	var bootErr *BootstrapError
+	if !errors.As(err, &bootErr) {
+		t.Fatalf("Expected *BootstrapError, got %v", err)
+	}
+	if ErrorCode(err) != ErrConfigLoadFailed.Code {
+		t.Errorf("Expected %s, got %q", ErrConfigLoadFailed.Code, ErrorCode(err))
+	}
+	if len(bootErr.Missing) != 2 {
+		t.Errorf("Expected empty.yaml and missing.yaml in report, got %v", bootErr)
+	}
+	if _, ok := snapshot.Get("db.yaml", ""); !ok {
+		t.Error("Expected partial snapshot to contain db.yaml")
+	}
+
+	config.Degraded = true
+	snapshot, err = client.Bootstrap(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Expected degraded mode to continue, got %v", err)
+	}
+	if !snapshot.Degraded || len(snapshot.Missing) != 2 {
+		t.Errorf("Expected degraded snapshot with 2 missing configs, got %+v", snapshot)
+	}
+}
+
+func TestBootstrapTimeout(t *testing.T) {
+	client := newTestClient(nacostest.NewConfigClient())
+
+	start := time.Now()
+	_, err := client.Bootstrap(context.Background(), BootstrapConfig{
+		Required:      []string{"app.yaml"},
+		Timeout:       50 * time.Millisecond,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	if err == nil {
+		t.Fatal("Expected error when required config never appears")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected bootstrap to stop at timeout, took %v", elapsed)
+	}
+}
+
+func TestParseConfigKey(t *testing.T) {
+	if key := ParseConfigKey("SHARED/redis.yaml"); key != (ConfigKey{DataId: "redis.yaml", Group: "SHARED"}) {
+		t.Errorf("ParseConfigKey(SHARED/redis.yaml) = %+v", key)
+	}
+	if key := ParseConfigKey("db.yaml"); key != (ConfigKey{DataId: "db.yaml"}) {
+		t.Errorf("ParseConfigKey(db.yaml) = %+v", key)
+	}
+}
@@ -0,0 +1,177 @@
+package nacos
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tjfoc/gmsm/sm4"
+)
+
+// CipherPrefix 需要加解密的dataId前缀
+const CipherPrefix = "cipher-"
+
+// 本地加密算法
+const (
+	CipherAESGCM = "aes-gcm"
+	CipherSM4GCM = "sm4-gcm"
+)
+
+// KeyProvider 配置加解密的密钥提供者
+// 密文以文本形式存储在Nacos中，dataId 可用于绑定密文防止被挪用到其他配置
+type KeyProvider interface {
+	Encrypt(ctx context.Context, dataId, plaintext string) (string, error)
+	Decrypt(ctx context.Context, dataId, ciphertext string) (string, error)
+}
+
+// WithKeyProvider 设置密钥提供者
+// 设置后 cipher- 开头的dataId在 GetConfig/ListenConfig 时自动解密，在 PublishConfig 时自动加密；
+// 未设置时内容原样读写
+func WithKeyProvider(provider KeyProvider) Option {
+	return func(c *NacosClient) {
+		c.keyProvider = provider
+	}
+}
+
+// IsCipherDataId 判断dataId是否为加密配置
+func IsCipherDataId(dataId string) bool {
+	return strings.HasPrefix(dataId, CipherPrefix)
+}
+
+// decryptContent 解密cipher-开头的配置，其他配置原样返回
+func (c *NacosClient) decryptContent(ctx context.Context, dataId, content string) (string, error) {
+	if c.keyProvider == nil || !IsCipherDataId(dataId) || content == "" {
+		return content, nil
+	}
+
+	plaintext, err := c.keyProvider.Decrypt(ctx, dataId, content)
+	if err != nil {
+		return "", NewNacosError(ErrDecryptFailed.Code, fmt.Sprintf("解密配置失败 [DataId: %s]", dataId), err)
+	}
+	return plaintext, nil
+}
+
+// encryptContent 加密cipher-开头的配置，其他配置原样返回
+func (c *NacosClient) encryptContent(ctx context.Context, dataId, content string) (string, error) {
+	if c.keyProvider == nil || !IsCipherDataId(dataId) {
+		return content, nil
+	}
+
+	ciphertext, err := c.keyProvider.Encrypt(ctx, dataId, content)
+	if err != nil {
+		return "", NewNacosError(ErrEncryptFailed.Code, fmt.Sprintf("加密配置失败 [DataId: %s]", dataId), err)
+	}
+	return ciphertext, nil
+}
+
+// LocalKeyProvider 使用本地密钥的密钥提供者，支持 AES-GCM 与 SM4-GCM
+// 密文格式为 base64(nonce || 密文 || tag)，dataId 作为附加认证数据
+type LocalKeyProvider struct {
+	aead cipher.AEAD
+}
+
+var _ KeyProvider = (*LocalKeyProvider)(nil)
+
+// NewFileKeyProvider 从密钥文件创建本地密钥提供者
+// 密钥文件内容可以是base64、hex编码或原始字节，AES密钥长度为16/24/32字节，SM4为16字节
+func NewFileKeyProvider(keyFile, algorithm string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %w", err)
+	}
+	return NewLocalKeyProvider(decodeKey(data), algorithm)
+}
+
+// NewLocalKeyProvider 使用给定密钥创建本地密钥提供者
+func NewLocalKeyProvider(key []byte, algorithm string) (*LocalKeyProvider, error) {
+	aead, err := newAEAD(key, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalKeyProvider{aead: aead}, nil
+}
+
+// Encrypt 加密配置内容
+func (p *LocalKeyProvider) Encrypt(ctx context.Context, dataId, plaintext string) (string, error) {
+	return sealString(p.aead, dataId, []byte(plaintext))
+}
+
+// Decrypt 解密配置内容
+func (p *LocalKeyProvider) Decrypt(ctx context.Context, dataId, ciphertext string) (string, error) {
+	plaintext, err := openString(p.aead, dataId, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// newAEAD 根据算法创建AEAD
+func newAEAD(key []byte, algorithm string) (cipher.AEAD, error) {
+	var (
+		block cipher.Block
+		err   error
+	)
+	switch strings.ToLower(algorithm) {
+	case "", CipherAESGCM:
+		block, err = aes.NewCipher(key)
+	case CipherSM4GCM:
+		block, err = sm4.NewCipher(key)
+	default:
+		return nil, fmt.Errorf("不支持的加密算法: %s，支持: %v", algorithm, []string{CipherAESGCM, CipherSM4GCM})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("创建加密器失败: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealString 加密并编码为base64文本
+func sealString(aead cipher.AEAD, dataId string, plaintext []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(dataId))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openString 解码base64文本并解密
+func openString(aead cipher.AEAD, dataId, ciphertext string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ciphertext))
+	if err != nil {
+		return nil, fmt.Errorf("密文不是有效的base64: %w", err)
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("密文长度不足")
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, []byte(dataId))
+	if err != nil {
+		return nil, fmt.Errorf("密文校验失败: %w", err)
+	}
+	return plaintext, nil
+}
+
+// decodeKey 解析密钥文件内容，依次尝试base64、hex与原始字节
+func decodeKey(data []byte) []byte {
+	text := string(bytes.TrimSpace(data))
+	if key, err := base64.StdEncoding.DecodeString(text); err == nil && validKeySize(len(key)) {
+		return key
+	}
+	if key, err := hex.DecodeString(text); err == nil && validKeySize(len(key)) {
+		return key
+	}
+	return []byte(text)
+}
+
+func validKeySize(n int) bool {
+	return n == 16 || n == 24 || n == 32
+}
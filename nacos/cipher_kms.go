@@ -0,0 +1,123 @@
+package nacos
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
+	kms "github.com/alibabacloud-go/kms-20160120/v3/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// KMSConfig 阿里云KMS配置
+type KMSConfig struct {
+	RegionId        string `mapstructure:"region_id"`
+	Endpoint        string `mapstructure:"endpoint"`
+	AccessKeyId     string `mapstructure:"access_key_id"`
+	AccessKeySecret string `mapstructure:"access_key_secret"`
+	KeyId           string `mapstructure:"key_id"`
+}
+
+// KMSKeyProvider 基于阿里云KMS的密钥提供者
+// 采用信封加密：每次加密由KMS生成数据密钥，内容在本地使用AES-GCM加密，
+// 密文格式为 "KMS加密的数据密钥:base64(nonce || 密文 || tag)"，不受KMS明文长度限制
+type KMSKeyProvider struct {
+	client *kms.Client
+	keyId  string
+}
+
+var _ KeyProvider = (*KMSKeyProvider)(nil)
+
+// NewKMSKeyProvider 创建阿里云KMS密钥提供者
+func NewKMSKeyProvider(config KMSConfig) (*KMSKeyProvider, error) {
+	if config.KeyId == "" {
+		return nil, fmt.Errorf("KMS密钥ID不能为空")
+	}
+
+	openapiConfig := &openapi.Config{
+		AccessKeyId:     tea.String(config.AccessKeyId),
+		AccessKeySecret: tea.String(config.AccessKeySecret),
+		RegionId:        tea.String(config.RegionId),
+	}
+	if config.Endpoint != "" {
+		openapiConfig.Endpoint = tea.String(config.Endpoint)
+	}
+
+	client, err := kms.NewClient(openapiConfig)
+	if err != nil {
+		return nil, fmt.Errorf("创建KMS客户端失败: %w", err)
+	}
+
+	return &KMSKeyProvider{client: client, keyId: config.KeyId}, nil
+}
+
+// Encrypt 加密配置内容
+func (p *KMSKeyProvider) Encrypt(ctx context.Context, dataId, plaintext string) (string, error) {
+	resp, err := p.client.GenerateDataKey(&kms.GenerateDataKeyRequest{
+		KeyId:             tea.String(p.keyId),
+		NumberOfBytes:     tea.Int32(32),
+		EncryptionContext: kmsEncryptionContext(dataId),
+	})
+	if err != nil {
+		return "", fmt.Errorf("生成数据密钥失败: %w", err)
+	}
+	if resp.Body == nil || resp.Body.Plaintext == nil || resp.Body.CiphertextBlob == nil {
+		return "", fmt.Errorf("生成数据密钥失败: 响应为空")
+	}
+
+	dataKey, err := base64.StdEncoding.DecodeString(tea.StringValue(resp.Body.Plaintext))
+	if err != nil {
+		return "", fmt.Errorf("解析数据密钥失败: %w", err)
+	}
+	aead, err := newAEAD(dataKey, CipherAESGCM)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := sealString(aead, dataId, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return tea.StringValue(resp.Body.CiphertextBlob) + ":" + sealed, nil
+}
+
+// Decrypt 解密配置内容
+func (p *KMSKeyProvider) Decrypt(ctx context.Context, dataId, ciphertext string) (string, error) {
+	encryptedKey, sealed, ok := strings.Cut(strings.TrimSpace(ciphertext), ":")
+	if !ok {
+		return "", fmt.Errorf("密文格式错误")
+	}
+
+	resp, err := p.client.Decrypt(&kms.DecryptRequest{
+		CiphertextBlob:    tea.String(encryptedKey),
+		EncryptionContext: kmsEncryptionContext(dataId),
+	})
+	if err != nil {
+		return "", fmt.Errorf("解密数据密钥失败: %w", err)
+	}
+	if resp.Body == nil || resp.Body.Plaintext == nil {
+		return "", fmt.Errorf("解密数据密钥失败: 响应为空")
+	}
+
+	dataKey, err := base64.StdEncoding.DecodeString(tea.StringValue(resp.Body.Plaintext))
+	if err != nil {
+		return "", fmt.Errorf("解析数据密钥失败: %w", err)
+	}
+	aead, err := newAEAD(dataKey, CipherAESGCM)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := openString(aead, dataId, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// kmsEncryptionContext 将dataId绑定到KMS加密上下文
+func kmsEncryptionContext(dataId string) map[string]interface{} {
+	return map[string]interface{}{"dataId": dataId}
+}
@@ -0,0 +1,112 @@
+package nacos
+
+import (
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+)
+
+func TestLocalKeyProvider(t *testing.T) {
+	for _, algorithm := range []string{CipherAESGCM, CipherSM4GCM} {
+		t.Run(algorithm, func(t *testing.T) {
+			provider, err := NewLocalKeyProvider([]byte("0123456789abcdef"), algorithm)
+			if err != nil {
+				t.Fatalf("NewLocalKeyProvider() error = %v", err)
+			}
+
+			ctx := context.Background()
+			ciphertext, err := provider.Encrypt(ctx, "cipher-db.yaml", "password: secret")
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+			if strings.Contains(ciphertext, "secret") {
+				t.Error("Expected ciphertext not to contain plaintext")
+			}
+
+			plaintext, err := provider.Decrypt(ctx, "cipher-db.yaml", ciphertext)
+			if err != nil {
+				t.Fatalf("Decrypt() error = %v", err)
+			}
+			if plaintext != "password: secret" {
+				t.Errorf("Expected 'password: secret', got %s", plaintext)
+			}
+
+			// 密文绑定dataId，挪用到其他dataId时解密失败
+			if _, err := provider.Decrypt(ctx, "cipher-other.yaml", ciphertext); err == nil {
+				t.Error("Expected decrypt with another dataId to fail")
+			}
+		})
+	}
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "nacos.key")
+	writeFile(t, keyFile, base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))+"\n")
+
+	provider, err := NewFileKeyProvider(keyFile, CipherAESGCM)
+	if err != nil {
+		t.Fatalf("NewFileKeyProvider() error = %v", err)
+	}
+	ciphertext, err := provider.Encrypt(context.Background(), "cipher-a", "a")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if _, err := provider.Decrypt(context.Background(), "cipher-a", ciphertext); err != nil {
+		t.Errorf("Decrypt() error = %v", err)
+	}
+
+	if _, err := NewLocalKeyProvider([]byte("short"), CipherAESGCM); err == nil {
+		t.Error("Expected invalid key size to fail")
+	}
+	if _, err := NewLocalKeyProvider([]byte("0123456789abcdef"), "des"); err == nil {
+		t.Error("Expected unsupported algorithm to fail")
+	}
+}
+
+func TestClientCipherConfig(t *testing.T) {
+	provider, err := NewLocalKeyProvider([]byte("0123456789abcdef"), CipherAESGCM)
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider() error = %v", err)
+	}
+
+	sdk := nacostest.NewConfigClient()
+	client := newTestClient(sdk, WithKeyProvider(provider))
+	ctx := context.Background()
+
+	var pushed string
+	if err := client.ListenConfig(ctx, "cipher-db.yaml", "DEFAULT_GROUP", func(content string) {
+		pushed = content
+	}); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+
+	if err := client.PublishConfig(ctx, "cipher-db.yaml", "DEFAULT_GROUP", "password: secret"); err != nil {
+		t.Fatalf("PublishConfig() error = %v", err)
+	}
+	if stored, _ := sdk.Get("cipher-db.yaml", "DEFAULT_GROUP"); stored == "password: secret" {
+		t.Error("Expected content to be stored encrypted")
+	}
+
+	content, err := client.GetConfig(ctx, "cipher-db.yaml", "DEFAULT_GROUP")
+	if err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	if content != "password: secret" {
+		t.Errorf("Expected 'password: secret', got %s", content)
+	}
+	if pushed != "password: secret" {
+		t.Errorf("Expected pushed 'password: secret', got %s", pushed)
+	}
+
+	// 非cipher-开头的配置不加密
+	if err := client.PublishConfig(ctx, "plain.yaml", "DEFAULT_GROUP", "a: 1"); err != nil {
+		t.Fatalf("PublishConfig() error = %v", err)
+	}
+	if stored, _ := sdk.Get("plain.yaml", "DEFAULT_GROUP"); stored != "a: 1" {
+		t.Errorf("Expected plain content, got %s", stored)
+	}
+}
@@ -3,29 +3,99 @@ package nacos
 import (
 	"context"
 	"fmt"
-	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/nacos-group/nacos-sdk-go/v2/clients"
 	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 )
 
 // NacosClient 封装了Nacos配置中心客户端
 type NacosClient struct {
 	client config_client.IConfigClient
-	config *Config
-	mu     sync.RWMutex
+	// ownsClient 表示配置客户端由 NewNacosClient 创建，Close 时关闭；WithConfigClient 注入的由调用方关闭
+	ownsClient bool
+	config     *Config
+	mu         sync.RWMutex
+
+	// SDK 对同一 dataId/group 只保留一个监听回调，这里自行维护回调列表并分发
+	listenMu  sync.Mutex
+	listeners map[string]*listenEntry
+
+	// 是否解析配置内容中的占位符
+	interpolation bool
+
+	// cipher-开头的dataId使用的密钥提供者
+	keyProvider KeyProvider
+
+	// 按 dataId/group 注册的校验器
+	validators validatorRegistry
+
+	// Prometheus 指标，未开启时为nil
+	metrics *clientMetrics
+
+	// 创建span使用的TracerProvider，为nil时使用otel全局TracerProvider
+	tracerProvider trace.TracerProvider
+
+	// 客户端日志，为nil时使用包级默认日志
+	logger Logger
+
+	// 健康检查使用的服务端探测，为nil时请求服务端的就绪检查接口
+	healthProbe HealthProbe
+	health      healthState
+
+	// GetConfigs 的并发数与请求合并
+	batchConcurrency int
+	fetchGroup       singleflight.Group
+
+	// 配置推送审计日志，ownsAudit 表示由客户端根据配置创建，Close 时关闭
+	audit     *AuditLog
+	ownsAudit bool
+
+	// 监听回调的异步分发与panic处理，dispatcher 为nil时同步执行回调
+	dispatcher   *dispatcher
+	panicHandler PanicHandler
+
+	// SDK不支持的描述、元数据读取使用的open API客户端，首次使用时创建
+	openAPIOnce sync.Once
+	openAPI     *httpConfigClient
+	openAPIErr  error
+}
+
+// listenEntry 同一 dataId/group 上注册的全部回调
+type listenEntry struct {
+	dataId    string
+	group     string
+	nextID    int
+	callbacks []pushHandler
+	lastPush  time.Time
+}
+
+// pushHandler 单个推送处理函数，返回推送未被投递的原因（解密、占位符解析或校验失败）
+type pushHandler struct {
+	id int
+	fn func(string) error
 }
 
+// Option 客户端可选配置
+type Option func(*NacosClient)
+
 var (
 	instance *NacosClient
 	once     sync.Once
 )
 
 // InitNacos 初始化Nacos客户端（单例模式）
-func InitNacos(configPath string) (*NacosClient, error) {
+// opts 仅在首次初始化时生效
+func InitNacos(configPath string, opts ...Option) (*NacosClient, error) {
 	var initErr error
 
 	once.Do(func() {
@@ -37,57 +107,117 @@ func InitNacos(configPath string) (*NacosClient, error) {
 			return
 		}
 
-		// 验证配置
-		if err := config.Validate(); err != nil {
-			initErr = fmt.Errorf("配置验证失败: %w", err)
-			return
-		}
+		instance, initErr = NewNacosClient(config, opts...)
+	})
 
-		// 创建客户端配置
-		clientConfig := constant.ClientConfig{
-			NamespaceId:         config.Nacos.Namespace,
-			TimeoutMs:           5000,
-			NotLoadCacheAtStart: true,
-			LogDir:              "/tmp/nacos/log",
-			CacheDir:            "/tmp/nacos/cache",
-			LogLevel:            "info", // 改为info级别，减少日志输出
-		}
+	return instance, initErr
+}
 
-		// 创建服务器配置
-		serverConfigs := []constant.ServerConfig{
-			{
-				IpAddr:      config.Nacos.Addr,
-				ContextPath: "/nacos",
-				Port:        config.Nacos.Port,
-				Scheme:      "http",
-			},
-		}
+// NewNacosClient 根据配置创建Nacos客户端（非单例）
+func NewNacosClient(config Config, opts ...Option) (*NacosClient, error) {
+	// 验证配置
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("配置验证失败: %w", err)
+	}
 
-		// 创建Nacos客户端
-		configClient, err := clients.NewConfigClient(
-			vo.NacosClientParam{
-				ClientConfig:  &clientConfig,
-				ServerConfigs: serverConfigs,
-			},
-		)
+	c := &NacosClient{config: &config}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.client == nil {
+		configClient, err := newConfigClient(config)
 		if err != nil {
-			initErr = fmt.Errorf("创建Nacos客户端失败: %w", err)
-			return
+			return nil, err
 		}
-
-		instance = &NacosClient{
-			client: configClient,
-			config: &config,
+		c.client, c.ownsClient = configClient, true
+	}
+	if c.audit == nil && config.Audit.File != "" {
+		audit, err := NewAuditLog(config.Audit)
+		if err != nil {
+			// 已创建的配置客户端不会返回给调用方，需在此关闭
+			if c.ownsClient {
+				c.client.CloseClient()
+			}
+			return nil, err
 		}
+		c.audit, c.ownsAudit = audit, true
+	}
+	c.routeSDKLogger()
 
-		log.Printf("Nacos客户端初始化成功，服务器: %s:%d", config.Nacos.Addr, config.Nacos.Port)
-	})
+	c.clientLogger().Info("Nacos客户端初始化成功",
+		LogKeyNamespace, config.Nacos.Namespace,
+		"addr", fmt.Sprintf("%s:%d", config.Nacos.Addr, config.Nacos.Port))
+	return c, nil
+}
 
-	return instance, initErr
+// newSDKConfigClient 根据配置创建 nacos-sdk-go 配置客户端
+func newSDKConfigClient(config Config) (config_client.IConfigClient, error) {
+	clientConfig, serverConfigs := sdkClientConfig(config.Nacos)
+
+	// 创建Nacos客户端
+	configClient, err := clients.NewConfigClient(
+		vo.NacosClientParam{
+			ClientConfig:  clientConfig,
+			ServerConfigs: serverConfigs,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("创建Nacos客户端失败: %w", err)
+	}
+
+	return configClient, nil
+}
+
+// sdkClientConfig 按配置生成SDK的客户端和服务端配置，未填写的项使用 DefaultConfig 中的默认值
+// 缓存目录按服务端地址区分，不同集群上同名命名空间的配置不会共用缓存文件
+func sdkClientConfig(config NacosConfig) (*constant.ClientConfig, []constant.ServerConfig) {
+	notLoadCache := config.NotLoadCache
+	config = mergeNacosConfig(DefaultConfig().Nacos, config)
+	config.NotLoadCache = notLoadCache
+
+	clientConfig := &constant.ClientConfig{
+		NamespaceId:         config.Namespace,
+		TimeoutMs:           uint64(config.TimeoutMs),
+		NotLoadCacheAtStart: config.NotLoadCache,
+		LogDir:              config.LogDir,
+		CacheDir:            filepath.Join(config.CacheDir, cacheDirName(config.Addr, config.Port)),
+		LogLevel:            config.LogLevel,
+		Username:            config.Username,
+		Password:            config.Password,
+	}
+	serverConfigs := []constant.ServerConfig{
+		{
+			IpAddr:      config.Addr,
+			ContextPath: config.ContextPath,
+			Port:        config.Port,
+			Scheme:      config.Scheme,
+		},
+	}
+	return clientConfig, serverConfigs
+}
+
+// cacheDirName 服务端地址对应的缓存子目录名，只保留可用于文件名的字符
+func cacheDirName(addr string, port uint64) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, addr)
+	return name + "_" + strconv.FormatUint(port, 10)
+}
+
+// WithConfigClient 使用指定的SDK配置客户端，不再连接配置中的Nacos服务端
+// 主要用于测试，配合 nacostest.ConfigClient 可以离线运行
+func WithConfigClient(client config_client.IConfigClient) Option {
+	return func(c *NacosClient) {
+		c.client = client
+	}
 }
 
 // GetConfig 获取配置
-func (c *NacosClient) GetConfig(ctx context.Context, dataId, group string) (string, error) {
+func (c *NacosClient) GetConfig(ctx context.Context, dataId, group string) (content string, err error) {
 	if c == nil || c.client == nil {
 		return "", fmt.Errorf("Nacos客户端未初始化")
 	}
@@ -103,19 +233,69 @@ func (c *NacosClient) GetConfig(ctx context.Context, dataId, group string) (stri
 		group = c.config.Nacos.Group
 	}
 
+	ctx, span := c.startSpan(ctx, "GetConfig", dataId, group)
+	defer func() {
+		span.SetAttributes(attrContentSize.Int(len(content)))
+		endSpan(span, err)
+	}()
+
+	start := time.Now()
+	defer func() { c.metrics.observe(opGet, dataId, group, start, err) }()
+
+	config, err := c.fetchConfig(ctx, dataId, group)
+	if err != nil {
+		return "", err
+	}
+
+	return c.processContent(ctx, dataId, group, config)
+}
+
+// fetchConfig 读取配置并解密（cipher-开头的dataId）
+func (c *NacosClient) fetchConfig(ctx context.Context, dataId, group string) (string, error) {
+	config, err := c.getRawConfig(dataId, group)
+	if err != nil {
+		return "", err
+	}
+
+	return c.decryptContent(ctx, dataId, config)
+}
+
+// getRawConfig 从Nacos读取未经处理的原始配置
+func (c *NacosClient) getRawConfig(dataId, group string) (string, error) {
 	config, err := c.client.GetConfig(vo.ConfigParam{
 		DataId: dataId,
 		Group:  group,
 	})
 	if err != nil {
-		return "", fmt.Errorf("获取配置失败 [DataId: %s, Group: %s]: %w", dataId, group, err)
+		return "", NewNacosError(ErrOperationFailed.Code, fmt.Sprintf("获取配置失败 [DataId: %s, Group: %s]", dataId, group), err)
 	}
 
+	c.health.markSuccess()
 	return config, nil
 }
 
-// PublishConfig 发布配置
+// processContent 对读取到的原始配置进行处理（占位符解析、校验等）
+func (c *NacosClient) processContent(ctx context.Context, dataId, group, content string) (string, error) {
+	if c.interpolation {
+		resolved, _, err := c.resolvePlaceholders(ctx, dataId, group, content)
+		if err != nil {
+			return "", err
+		}
+		content = resolved
+	}
+	if err := c.validateContent(dataId, group, content); err != nil {
+		return "", err
+	}
+	return content, nil
+}
+
+// PublishConfig 发布配置，配置类型按dataId扩展名推断，需要附带其他元数据时使用 PublishConfigWithOptions
 func (c *NacosClient) PublishConfig(ctx context.Context, dataId, group, content string) error {
+	return c.PublishConfigWithOptions(ctx, dataId, group, content, PublishOptions{})
+}
+
+// DeleteConfig 删除配置
+func (c *NacosClient) DeleteConfig(ctx context.Context, dataId, group string) (err error) {
 	if c == nil || c.client == nil {
 		return fmt.Errorf("Nacos客户端未初始化")
 	}
@@ -131,30 +311,50 @@ func (c *NacosClient) PublishConfig(ctx context.Context, dataId, group, content
 		group = c.config.Nacos.Group
 	}
 
-	success, err := c.client.PublishConfig(vo.ConfigParam{
-		DataId:  dataId,
-		Group:   group,
-		Content: content,
+	_, span := c.startSpan(ctx, "DeleteConfig", dataId, group)
+	defer func() { endSpan(span, err) }()
+
+	start := time.Now()
+	defer func() { c.metrics.observe(opDelete, dataId, group, start, err) }()
+
+	success, err := c.client.DeleteConfig(vo.ConfigParam{
+		DataId: dataId,
+		Group:  group,
 	})
 	if err != nil {
-		return fmt.Errorf("发布配置失败 [DataId: %s, Group: %s]: %w", dataId, group, err)
+		return NewNacosError(ErrDeleteFailed.Code, fmt.Sprintf("删除配置失败 [DataId: %s, Group: %s]", dataId, group), err)
 	}
 
 	if !success {
-		return fmt.Errorf("发布配置失败，返回false")
+		return NewNacosError(ErrDeleteFailed.Code, "删除配置失败，返回false", nil)
 	}
 
 	return nil
 }
 
-// DeleteConfig 删除配置
-func (c *NacosClient) DeleteConfig(ctx context.Context, dataId, group string) error {
-	if c == nil || c.client == nil {
-		return fmt.Errorf("Nacos客户端未初始化")
+// ListenConfig 监听配置变化
+func (c *NacosClient) ListenConfig(ctx context.Context, dataId, group string, callback func(string)) error {
+	var cb func(context.Context, string)
+	if callback != nil {
+		cb = func(_ context.Context, content string) {
+			callback(content)
+		}
 	}
+	return c.ListenConfigContext(ctx, dataId, group, cb)
+}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// ListenConfigContext 监听配置变化，回调收到的ctx携带本次推送的span，
+// 该span关联到注册监听时的span，便于追踪由配置变化触发的后续处理
+func (c *NacosClient) ListenConfigContext(ctx context.Context, dataId, group string, callback func(context.Context, string)) error {
+	_, err := c.listen(ctx, dataId, group, callback)
+	return err
+}
+
+// listen 注册带追踪的监听回调，返回取消该回调的函数
+func (c *NacosClient) listen(ctx context.Context, dataId, group string, callback func(context.Context, string)) (cancel func(), err error) {
+	if c == nil || c.client == nil {
+		return nil, fmt.Errorf("Nacos客户端未初始化")
+	}
 
 	// 使用默认值如果参数为空
 	if dataId == "" {
@@ -164,25 +364,20 @@ func (c *NacosClient) DeleteConfig(ctx context.Context, dataId, group string) er
 		group = c.config.Nacos.Group
 	}
 
-	success, err := c.client.DeleteConfig(vo.ConfigParam{
-		DataId: dataId,
-		Group:  group,
-	})
-	if err != nil {
-		return fmt.Errorf("删除配置失败 [DataId: %s, Group: %s]: %w", dataId, group, err)
-	}
+	_, span := c.startSpan(ctx, "ListenConfig", dataId, group)
+	defer func() { endSpan(span, err) }()
 
-	if !success {
-		return fmt.Errorf("删除配置失败，返回false")
+	var cb func(string)
+	if callback != nil {
+		cb = c.tracedCallback(span.SpanContext(), dataId, group, callback)
 	}
-
-	return nil
+	return c.addListener(dataId, group, cb)
 }
 
-// ListenConfig 监听配置变化
-func (c *NacosClient) ListenConfig(ctx context.Context, dataId, group string, callback func(string)) error {
+// addListener 注册监听回调，回调收到的是经过处理的配置内容，返回取消该回调的函数
+func (c *NacosClient) addListener(dataId, group string, callback func(string)) (cancel func(), err error) {
 	if c == nil || c.client == nil {
-		return fmt.Errorf("Nacos客户端未初始化")
+		return nil, fmt.Errorf("Nacos客户端未初始化")
 	}
 
 	// 使用默认值如果参数为空
@@ -193,20 +388,131 @@ func (c *NacosClient) ListenConfig(ctx context.Context, dataId, group string, ca
 		group = c.config.Nacos.Group
 	}
 
-	err := c.client.ListenConfig(vo.ConfigParam{
-		DataId: dataId,
-		Group:  group,
-		OnChange: func(namespace, group, dataId, data string) {
-			if callback != nil {
-				callback(data)
-			}
-		},
+	start := time.Now()
+	defer func() { c.metrics.observe(opListen, dataId, group, start, err) }()
+
+	handler := c.validatingCallback(dataId, group, callback)
+	if c.interpolation {
+		return c.watchPlaceholders(dataId, group, handler)
+	}
+	return c.watchConfig(dataId, group, handler)
+}
+
+// watchConfig 注册监听回调，推送内容解密后再交给回调
+func (c *NacosClient) watchConfig(dataId, group string, handler func(string) error) (func(), error) {
+	return c.subscribe(dataId, group, func(data string) error {
+		content, err := c.decryptContent(context.Background(), dataId, data)
+		if err != nil {
+			c.clientLogger().Error("解密推送的配置失败", c.logFields(dataId, group, err)...)
+			return err
+		}
+		if handler != nil {
+			return handler(content)
+		}
+		return nil
 	})
-	if err != nil {
-		return fmt.Errorf("监听配置失败 [DataId: %s, Group: %s]: %w", dataId, group, err)
+}
+
+// subscribe 注册原始配置的监听回调，返回取消该回调的函数
+// 同一 dataId/group 只向SDK注册一次，后续回调由客户端自行分发
+func (c *NacosClient) subscribe(dataId, group string, handler func(string) error) (func(), error) {
+	key := group + "@@" + dataId
+
+	c.listenMu.Lock()
+
+	if c.listeners == nil {
+		c.listeners = make(map[string]*listenEntry)
 	}
 
-	return nil
+	entry, ok := c.listeners[key]
+	if !ok {
+		entry = &listenEntry{dataId: dataId, group: group}
+		err := c.client.ListenConfig(vo.ConfigParam{
+			DataId: dataId,
+			Group:  group,
+			OnChange: func(namespace, group, dataId, data string) {
+				c.metrics.push(dataId, group)
+				c.notifyListeners(namespace, dataId, group, data)
+			},
+		})
+		if err != nil {
+			c.listenMu.Unlock()
+			return nil, NewNacosError(ErrListenFailed.Code, fmt.Sprintf("监听配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+		}
+		c.listeners[key] = entry
+	}
+
+	id := entry.nextID
+	entry.nextID++
+	if handler != nil {
+		entry.callbacks = append(entry.callbacks, pushHandler{id: id, fn: handler})
+	}
+	c.listenMu.Unlock()
+
+	if !ok {
+		// 读取审计diff的基准需要请求服务端，不能持有 listenMu
+		c.auditSeed(dataId, group)
+	}
+
+	cancel := func() {
+		c.listenMu.Lock()
+		defer c.listenMu.Unlock()
+		for i, cb := range entry.callbacks {
+			if cb.id == id {
+				entry.callbacks = append(entry.callbacks[:i:i], entry.callbacks[i+1:]...)
+				break
+			}
+		}
+	}
+	return cancel, nil
+}
+
+// notifyListeners 将配置变更分发给同一 dataId/group 上的所有回调，投递后写入审计记录
+func (c *NacosClient) notifyListeners(namespace, dataId, group, data string) {
+	key := group + "@@" + dataId
+	c.listenMu.Lock()
+	if entry, ok := c.listeners[key]; ok {
+		entry.lastPush = time.Now()
+	}
+	c.listenMu.Unlock()
+	c.health.markSuccess()
+
+	deliver := func(data string) {
+		err := c.runCallbacks(key, data)
+		if err == nil {
+			c.metrics.applied(dataId, group)
+		}
+		c.auditPush(namespace, dataId, group, data, err)
+	}
+	if c.dispatcher != nil {
+		c.dispatcher.enqueue(key, data, deliver)
+		return
+	}
+	deliver(data)
+}
+
+// runCallbacks 依次执行配置的全部回调，单个回调的panic不影响其他回调
+// 返回第一个拒绝该推送的原因，全部投递成功时返回nil
+func (c *NacosClient) runCallbacks(key, data string) error {
+	c.listenMu.Lock()
+	entry, ok := c.listeners[key]
+	if !ok {
+		c.listenMu.Unlock()
+		return nil
+	}
+	handlers := make([]func(string) error, 0, len(entry.callbacks))
+	for _, cb := range entry.callbacks {
+		handlers = append(handlers, cb.fn)
+	}
+	c.listenMu.Unlock()
+
+	var rejected error
+	for _, handler := range handlers {
+		if err := c.safeCallback(entry.dataId, entry.group, handler, data); err != nil && rejected == nil {
+			rejected = err
+		}
+	}
+	return rejected
 }
 
 // Close 关闭客户端
@@ -215,9 +521,21 @@ func (c *NacosClient) Close() error {
 		return nil
 	}
 
-	// Nacos SDK 没有提供显式的关闭方法
-	// 这里可以添加清理逻辑
-	log.Println("Nacos客户端已关闭")
+	if c.dispatcher != nil {
+		c.dispatcher.close()
+	}
+	if c.ownsClient {
+		c.client.CloseClient()
+	}
+	if c.openAPI != nil {
+		c.openAPI.CloseClient()
+	}
+	if c.ownsAudit {
+		if err := c.audit.Close(); err != nil {
+			c.clientLogger().Warn("关闭审计日志失败", LogKeyError, err.Error())
+		}
+	}
+	c.clientLogger().Info("Nacos客户端已关闭", LogKeyNamespace, c.config.Nacos.Namespace)
 	return nil
 }
 
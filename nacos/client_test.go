@@ -134,6 +134,37 @@ func TestDefaultConfig(t *testing.T) {
 	}
 }
 
+func TestSDKClientConfig(t *testing.T) {
+	clientConfig, servers := sdkClientConfig(NacosConfig{
+		Namespace:    "dev",
+		Addr:         "10.0.0.1",
+		Port:         8848,
+		TimeoutMs:    3000,
+		CacheDir:     "/data/nacos/cache",
+		NotLoadCache: true,
+		Scheme:       "https",
+		ContextPath:  "/config",
+	})
+	if clientConfig.TimeoutMs != 3000 || clientConfig.NamespaceId != "dev" || !clientConfig.NotLoadCacheAtStart {
+		t.Errorf("Unexpected client config: %+v", clientConfig)
+	}
+	if clientConfig.CacheDir != "/data/nacos/cache/10.0.0.1_8848" {
+		t.Errorf("Expected cache dir per server, got %s", clientConfig.CacheDir)
+	}
+	if clientConfig.LogDir != "/tmp/nacos/log" || clientConfig.LogLevel != "info" {
+		t.Errorf("Expected defaults for unset fields, got LogDir=%s LogLevel=%s", clientConfig.LogDir, clientConfig.LogLevel)
+	}
+	if len(servers) != 1 || servers[0].Scheme != "https" || servers[0].ContextPath != "/config" || servers[0].Port != 8848 {
+		t.Errorf("Unexpected server config: %+v", servers)
+	}
+
+	// 同一命名空间、不同集群的客户端使用不同的缓存目录
+	other, _ := sdkClientConfig(NacosConfig{Namespace: "dev", Addr: "10.0.0.2", Port: 8848})
+	if other.CacheDir == clientConfig.CacheDir || other.TimeoutMs != 5000 {
+		t.Errorf("Unexpected config for another cluster: CacheDir=%s TimeoutMs=%d", other.CacheDir, other.TimeoutMs)
+	}
+}
+
 func TestGetServerURL(t *testing.T) {
 	config := &Config{
 		Nacos: NacosConfig{
@@ -0,0 +1,108 @@
+// nacosctl Nacos配置管理命令行工具
+//
+// 用法:
+//
+//	nacosctl sync -config config/application.yaml -dir ./configs [-prune] [-protect 模式]... [-dry-run]
+//
+// sync 将 <namespace>/<group>/<dataId> 结构的目录同步到Nacos，namespace 为配置文件中的客户端名称
+// （default 或 namespaces 下的键），详见 nacos.Reconciler。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/fuyx123/common-package/nacos"
+)
+
+// loadManager 根据配置文件创建 Manager，测试时替换
+var loadManager = func(configPath string) (*nacos.Manager, error) {
+	return nacos.NewManagerFromFile(configPath)
+}
+
+const usage = `用法: nacosctl <命令> [参数]
+
+命令:
+  sync    将目录中的配置同步到Nacos
+
+使用 "nacosctl <命令> -h" 查看命令的参数
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 执行命令并返回退出码
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "sync":
+		return runSync(ctx, args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "未知命令: %s\n\n%s", args[0], usage)
+		return 2
+	}
+}
+
+// runSync 执行 sync 命令
+func runSync(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "config/application.yaml", "Nacos客户端配置文件")
+	dir := fs.String("dir", "", "配置目录，结构为 <namespace>/<group>/<dataId>（必填）")
+	prune := fs.Bool("prune", false, "删除线上存在但目录中没有的配置")
+	dryRun := fs.Bool("dry-run", false, "只输出diff，不修改线上配置")
+	var protected []string
+	fs.Func("protect", "受保护配置的模式，匹配 namespace/group/dataId，可重复指定", func(pattern string) error {
+		protected = append(protected, pattern)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *dir == "" || fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "sync: 必须通过 -dir 指定配置目录，且不接受其他参数")
+		fs.Usage()
+		return 2
+	}
+
+	manager, err := loadManager(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "sync: %v\n", err)
+		return 1
+	}
+	defer manager.Close()
+
+	reconciler := &nacos.Reconciler{
+		Manager:   manager,
+		Dir:       *dir,
+		Prune:     *prune,
+		Protected: protected,
+		DryRun:    *dryRun,
+		Output:    stdout,
+	}
+	if _, err := reconciler.Reconcile(ctx); err != nil {
+		fmt.Fprintf(stderr, "sync: %v\n", err)
+		return 1
+	}
+	if *dryRun {
+		fmt.Fprintln(stdout, "dry-run: 未修改线上配置")
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos"
+	"github.com/fuyx123/common-package/nacos/nacostest"
+)
+
+func TestRun(t *testing.T) {
+	fake := nacostest.NewConfigClient()
+	fake.Set("old.yaml", "DEFAULT_GROUP", "stale: true")
+	loadManager = func(configPath string) (*nacos.Manager, error) {
+		if configPath != "app.yaml" {
+			return nil, errors.New("unexpected config path " + configPath)
+		}
+		config := *nacos.DefaultConfig()
+		config.Nacos.Addr = "127.0.0.1"
+		config.Nacos.Port = 8848
+		config.Nacos.Dataid = "app.yaml"
+		return nacos.NewManager(config, nacos.WithConfigClient(fake))
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "default", "DEFAULT_GROUP"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "default", "DEFAULT_GROUP", "app.yaml"), []byte("port: 8080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	var stdout, stderr strings.Builder
+	code := run(ctx, []string{"sync", "-config", "app.yaml", "-dir", dir, "-prune", "-dry-run"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("sync -dry-run exit code = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "==> [create] default/DEFAULT_GROUP/app.yaml") || !strings.Contains(stdout.String(), "dry-run") {
+		t.Errorf("Unexpected dry run output:\n%s", stdout.String())
+	}
+	if _, ok := fake.Get("app.yaml", ""); ok {
+		t.Error("Expected dry run not to publish")
+	}
+
+	stdout.Reset()
+	code = run(ctx, []string{"sync", "-config", "app.yaml", "-dir", dir, "-prune", "-protect", "*/*/old.yaml"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("sync exit code = %d, stderr: %s", code, stderr.String())
+	}
+	if content, _ := fake.Get("app.yaml", ""); content != "port: 8080\n" {
+		t.Errorf("Expected app.yaml to be published, got %q", content)
+	}
+	if _, ok := fake.Get("old.yaml", ""); !ok {
+		t.Error("Expected protected old.yaml to be kept")
+	}
+
+	for _, args := range [][]string{nil, {"deploy"}, {"sync"}, {"sync", "-dir", dir, "extra"}} {
+		if code := run(ctx, args, &stdout, &stderr); code != 2 {
+			t.Errorf("run(%v) exit code = %d, want 2", args, code)
+		}
+	}
+	if code := run(ctx, []string{"sync", "-config", "missing.yaml", "-dir", dir}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 when the manager cannot be created, got %d", code)
+	}
+}
@@ -3,6 +3,8 @@ package nacos
 import (
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -12,7 +14,12 @@ var conf Config
 
 // Config Nacos配置结构
 type Config struct {
-	Nacos NacosConfig `mapstructure:"nacos"`
+	Nacos     NacosConfig     `mapstructure:"nacos"`
+	Source    SourceConfig    `mapstructure:"source"`
+	Bootstrap BootstrapConfig `mapstructure:"bootstrap"`
+	Audit     AuditConfig     `mapstructure:"audit"`
+	// Namespaces 按名称配置的其他命名空间（或集群），见 NewManager
+	Namespaces map[string]NacosConfig `mapstructure:"namespaces"`
 }
 
 // NacosConfig Nacos具体配置
@@ -30,6 +37,11 @@ type NacosConfig struct {
 	NotLoadCache bool   `mapstructure:"not_load_cache"`
 	Scheme       string `mapstructure:"scheme"`
 	ContextPath  string `mapstructure:"context_path"`
+	// 开启鉴权时的用户名和密码
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// Backend 访问Nacos的后端：sdk（默认，nacos-sdk-go）或 http（只依赖标准库的 nacoshttp）
+	Backend string `mapstructure:"backend"`
 }
 
 // DefaultConfig 返回默认配置
@@ -48,32 +60,91 @@ func DefaultConfig() *Config {
 	}
 }
 
+// ProfileEnv 指定环境配置(profile)的环境变量名
+const ProfileEnv = "APP_PROFILE"
+
+// envBindings 配置项与环境变量的对应关系，环境变量优先级最高
+var envBindings = map[string]string{
+	"nacos.namespace":      "NACOS_NAMESPACE",
+	"nacos.addr":           "NACOS_ADDR",
+	"nacos.port":           "NACOS_PORT",
+	"nacos.dataid":         "NACOS_DATAID",
+	"nacos.group":          "NACOS_GROUP",
+	"nacos.timeout_ms":     "NACOS_TIMEOUT_MS",
+	"nacos.log_level":      "NACOS_LOG_LEVEL",
+	"nacos.log_dir":        "NACOS_LOG_DIR",
+	"nacos.cache_dir":      "NACOS_CACHE_DIR",
+	"nacos.not_load_cache": "NACOS_NOT_LOAD_CACHE",
+	"nacos.scheme":         "NACOS_SCHEME",
+	"nacos.context_path":   "NACOS_CONTEXT_PATH",
+	"nacos.username":       "NACOS_USERNAME",
+	"nacos.password":       "NACOS_PASSWORD",
+	"nacos.backend":        "NACOS_BACKEND",
+	"source.type":          "CONFIG_SOURCE_TYPE",
+	"source.dir":           "CONFIG_SOURCE_DIR",
+	"source.prefix":        "CONFIG_SOURCE_PREFIX",
+}
+
 // LoadConfig 加载配置文件
+// 配置优先级（由高到低）：环境变量 > application-{profile}.yaml > 主配置文件 > 默认值，
+// profile 由环境变量 APP_PROFILE 指定
 func LoadConfig(configPath string) (Config, error) {
-	viper.SetConfigFile(configPath)
+	return LoadConfigWithProfile(configPath, os.Getenv(ProfileEnv))
+}
+
+// LoadConfigWithProfile 加载配置文件并叠加指定profile的配置
+// 使用独立的viper实例，不会影响应用自身的全局viper状态
+func LoadConfigWithProfile(configPath, profile string) (Config, error) {
+	v := viper.New()
+	v.SetConfigFile(configPath)
 
 	// 设置默认值
-	viper.SetDefault("nacos.timeout_ms", 5000)
-	viper.SetDefault("nacos.log_level", "info")
-	viper.SetDefault("nacos.log_dir", "/tmp/nacos/log")
-	viper.SetDefault("nacos.cache_dir", "/tmp/nacos/cache")
-	viper.SetDefault("nacos.not_load_cache", true)
-	viper.SetDefault("nacos.scheme", "http")
-	viper.SetDefault("nacos.context_path", "/nacos")
-	viper.SetDefault("nacos.group", "DEFAULT_GROUP")
-
-	if err := viper.ReadInConfig(); err != nil {
+	v.SetDefault("nacos.timeout_ms", 5000)
+	v.SetDefault("nacos.log_level", "info")
+	v.SetDefault("nacos.log_dir", "/tmp/nacos/log")
+	v.SetDefault("nacos.cache_dir", "/tmp/nacos/cache")
+	v.SetDefault("nacos.not_load_cache", true)
+	v.SetDefault("nacos.scheme", "http")
+	v.SetDefault("nacos.context_path", "/nacos")
+	v.SetDefault("nacos.group", "DEFAULT_GROUP")
+
+	if err := v.ReadInConfig(); err != nil {
 		return Config{}, fmt.Errorf("读取配置文件失败: %w", err)
 	}
 
+	// 叠加profile配置，文件不存在时忽略
+	if profile != "" {
+		profilePath := ProfileConfigPath(configPath, profile)
+		if _, err := os.Stat(profilePath); err == nil {
+			v.SetConfigFile(profilePath)
+			if err := v.MergeInConfig(); err != nil {
+				return Config{}, fmt.Errorf("读取profile配置文件失败 [%s]: %w", profilePath, err)
+			}
+		}
+	}
+
+	// 绑定环境变量
+	for key, env := range envBindings {
+		if err := v.BindEnv(key, env); err != nil {
+			return Config{}, fmt.Errorf("绑定环境变量失败 [%s]: %w", env, err)
+		}
+	}
+
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config); err != nil {
 		return Config{}, fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
 	return config, nil
 }
 
+// ProfileConfigPath 返回profile配置文件路径
+// 例如 conf/application.yaml 在 profile 为 dev 时对应 conf/application-dev.yaml
+func ProfileConfigPath(configPath, profile string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "-" + profile + ext
+}
+
 // Validate 验证配置
 func (c *Config) Validate() error {
 	if c.Nacos.Addr == "" {
@@ -134,6 +205,13 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// 验证后端
+	switch strings.ToLower(c.Nacos.Backend) {
+	case "", BackendSDK, BackendHTTP:
+	default:
+		return fmt.Errorf("无效的后端: %s，支持: %v", c.Nacos.Backend, []string{BackendSDK, BackendHTTP})
+	}
+
 	return nil
 }
 
@@ -0,0 +1,122 @@
+package nacos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+}
+
+func TestLoadConfigWithProfile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "application.yaml")
+	writeFile(t, configPath, `
+nacos:
+  addr: "127.0.0.1"
+  port: 8848
+  dataid: "app"
+  namespace: "base"
+`)
+	writeFile(t, filepath.Join(dir, "application-dev.yaml"), `
+nacos:
+  namespace: "dev"
+`)
+
+	config, err := LoadConfigWithProfile(configPath, "dev")
+	if err != nil {
+		t.Fatalf("LoadConfigWithProfile() error = %v", err)
+	}
+	if config.Nacos.Namespace != "dev" {
+		t.Errorf("Expected Namespace = 'dev', got %s", config.Nacos.Namespace)
+	}
+	if config.Nacos.Addr != "127.0.0.1" {
+		t.Errorf("Expected Addr = '127.0.0.1', got %s", config.Nacos.Addr)
+	}
+	if config.Nacos.Group != "DEFAULT_GROUP" {
+		t.Errorf("Expected Group = 'DEFAULT_GROUP', got %s", config.Nacos.Group)
+	}
+
+	// profile文件不存在时忽略
+	config, err = LoadConfigWithProfile(configPath, "prod")
+	if err != nil {
+		t.Fatalf("LoadConfigWithProfile() error = %v", err)
+	}
+	if config.Nacos.Namespace != "base" {
+		t.Errorf("Expected Namespace = 'base', got %s", config.Nacos.Namespace)
+	}
+}
+
+func TestLoadConfigEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "application.yaml")
+	writeFile(t, configPath, `
+nacos:
+  addr: "127.0.0.1"
+  port: 8848
+  dataid: "app"
+`)
+	writeFile(t, filepath.Join(dir, "application-test.yaml"), `
+nacos:
+  namespace: "test"
+`)
+
+	t.Setenv("NACOS_ADDR", "nacos.internal")
+	t.Setenv("NACOS_PORT", "9848")
+	t.Setenv("NACOS_NAMESPACE", "from-env")
+	t.Setenv(ProfileEnv, "test")
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Nacos.Addr != "nacos.internal" {
+		t.Errorf("Expected Addr = 'nacos.internal', got %s", config.Nacos.Addr)
+	}
+	if config.Nacos.Port != 9848 {
+		t.Errorf("Expected Port = 9848, got %d", config.Nacos.Port)
+	}
+	if config.Nacos.Namespace != "from-env" {
+		t.Errorf("Expected Namespace = 'from-env', got %s", config.Nacos.Namespace)
+	}
+}
+
+func TestLoadConfigIsolatedFromGlobalViper(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "application.yaml")
+	writeFile(t, configPath, `
+nacos:
+  addr: "127.0.0.1"
+  port: 8848
+  dataid: "app"
+`)
+
+	viper.Set("nacos.addr", "polluted")
+	defer viper.Reset()
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Nacos.Addr != "127.0.0.1" {
+		t.Errorf("Expected Addr = '127.0.0.1', got %s", config.Nacos.Addr)
+	}
+	if viper.IsSet("nacos.dataid") {
+		t.Error("Expected LoadConfig not to touch global viper")
+	}
+}
+
+func TestProfileConfigPath(t *testing.T) {
+	actual := ProfileConfigPath("conf/application.yaml", "dev")
+	expected := "conf/application-dev.yaml"
+	if actual != expected {
+		t.Errorf("Expected %s, got %s", expected, actual)
+	}
+}
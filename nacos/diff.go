@@ -0,0 +1,158 @@
+package nacos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 生成统一格式diff时的上下文行数，以及去掉首尾相同行后LCS表的最大单元数（约1MB）
+const (
+	diffContextLines = 3
+	diffMaxCells     = 250000
+)
+
+// unifiedDiff 生成两段文本的统一格式diff，内容相同时返回空字符串
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	a, b := splitLines(oldText), splitLines(newText)
+	ops, ok := diffLines(a, b)
+	if !ok {
+		return fmt.Sprintf("--- %s\n+++ %s\n@@ 变化范围过大，未生成diff @@\n", oldName, newName)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(ops); {
+		// 找到下一处变化
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// 合并间隔不超过两倍上下文的变化为一个hunk
+		from := max(start-diffContextLines, 0)
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContextLines {
+				break
+			}
+		}
+		to := min(end+diffContextLines, len(ops))
+		writeHunk(&sb, ops[from:to])
+		start = to
+	}
+	return sb.String()
+}
+
+// diffOp 一行diff，kind 为 ' '、'-' 或 '+'
+type diffOp struct {
+	kind       byte
+	line       string
+	oldN, newN int
+}
+
+// diffLines 基于最长公共子序列计算逐行差异
+// 首尾相同的行直接作为上下文，只对中间变化的部分建LCS表；表超过 diffMaxCells 时返回 false
+func diffLines(a, b []string) ([]diffOp, bool) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(midA), len(midB)
+	if n > 0 && m > 0 && n*m > diffMaxCells {
+		return nil, false
+	}
+
+	// lcs[i*(m+1)+j] 为 midA[i:] 与 midB[j:] 的LCS长度
+	lcs := make([]int32, (n+1)*(m+1))
+	at := func(i, j int) int32 { return lcs[i*(m+1)+j] }
+	if n > 0 && m > 0 {
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i*(m+1)+j] = at(i+1, j+1) + 1
+				} else {
+					lcs[i*(m+1)+j] = max(at(i+1, j), at(i, j+1))
+				}
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b)-prefix-suffix)
+	for k := 0; k < prefix; k++ {
+		ops = append(ops, diffOp{kind: ' ', line: a[k], oldN: k + 1, newN: k + 1})
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && midA[i] == midB[j]:
+			ops = append(ops, diffOp{kind: ' ', line: midA[i], oldN: prefix + i + 1, newN: prefix + j + 1})
+			i++
+			j++
+		case i < n && (j == m || at(i+1, j) >= at(i, j+1)):
+			ops = append(ops, diffOp{kind: '-', line: midA[i], oldN: prefix + i + 1, newN: prefix + j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: midB[j], oldN: prefix + i, newN: prefix + j + 1})
+			j++
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		oldN, newN := len(a)-suffix+k+1, len(b)-suffix+k+1
+		ops = append(ops, diffOp{kind: ' ', line: a[oldN-1], oldN: oldN, newN: newN})
+	}
+	return ops, true
+}
+
+// writeHunk 输出一个hunk
+func writeHunk(sb *strings.Builder, ops []diffOp) {
+	var oldStart, newStart, oldCount, newCount int
+	for _, op := range ops {
+		if op.kind != '+' {
+			if oldCount == 0 {
+				oldStart = op.oldN
+			}
+			oldCount++
+		}
+		if op.kind != '-' {
+			if newCount == 0 {
+				newStart = op.newN
+			}
+			newCount++
+		}
+	}
+	// 没有旧行或新行时，起始行号为插入/删除位置之前的行
+	if oldCount == 0 {
+		oldStart = ops[0].oldN
+	}
+	if newCount == 0 {
+		newStart = ops[0].newN
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, op := range ops {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.line)
+		sb.WriteByte('\n')
+	}
+}
+
+// splitLines 按行拆分，忽略末尾换行
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,173 @@
+package nacos
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// PanicHandler 监听回调发生panic时调用，recovered 为 recover() 的返回值
+type PanicHandler func(dataId, group string, recovered interface{}, stack []byte)
+
+// WithPanicHandler 设置监听回调panic的处理函数
+// 回调的panic总会被恢复并记录日志（开启指标时计入 nacos_client_callback_panics_total），
+// 不会导致进程退出，也不影响同一配置的其他回调
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(c *NacosClient) {
+		c.panicHandler = handler
+	}
+}
+
+// DispatcherOptions 异步分发推送的配置
+type DispatcherOptions struct {
+	// Debounce 同一配置在该时间内连续多次推送时只投递最后一次，0表示不等待
+	Debounce time.Duration
+}
+
+// WithDispatcher 在独立的goroutine中执行监听回调，不阻塞SDK的推送goroutine
+//
+// 每个 dataId/group 有各自的串行队列：同一配置的回调按顺序执行，不同配置互不影响。
+// 回调执行期间收到的推送会合并，只保留最新的内容，保证最后投递的总是最新版本。
+// 未设置时回调在SDK的推送goroutine中同步执行
+func WithDispatcher(opts DispatcherOptions) Option {
+	return func(c *NacosClient) {
+		c.dispatcher = newDispatcher(opts)
+	}
+}
+
+// dispatcher 按配置串行、合并地投递推送
+type dispatcher struct {
+	debounce time.Duration
+
+	mu     sync.Mutex
+	queues map[string]*dispatchQueue
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// dispatchQueue 单个配置的待投递内容
+type dispatchQueue struct {
+	// pending 尚未投递的最新内容
+	pending *string
+	// seq 每次推送递增，用于判断防抖等待期间是否有新的推送
+	seq     uint64
+	running bool
+}
+
+func newDispatcher(opts DispatcherOptions) *dispatcher {
+	return &dispatcher{
+		debounce: opts.Debounce,
+		queues:   make(map[string]*dispatchQueue),
+		done:     make(chan struct{}),
+	}
+}
+
+// enqueue 加入一次推送，该配置没有正在运行的投递goroutine时启动一个
+func (d *dispatcher) enqueue(key, data string, deliver func(string)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+
+	q, ok := d.queues[key]
+	if !ok {
+		q = &dispatchQueue{}
+		d.queues[key] = q
+	}
+	q.pending = &data
+	q.seq++
+	if q.running {
+		return
+	}
+
+	q.running = true
+	d.wg.Add(1)
+	go d.run(key, q, deliver)
+}
+
+// run 依次投递最新内容，直到没有新的推送
+func (d *dispatcher) run(key string, q *dispatchQueue, deliver func(string)) {
+	defer d.wg.Done()
+
+	for {
+		if !d.settle(q) {
+			return
+		}
+
+		d.mu.Lock()
+		if q.pending == nil || d.closed {
+			q.running = false
+			delete(d.queues, key)
+			d.mu.Unlock()
+			return
+		}
+		data := *q.pending
+		q.pending = nil
+		d.mu.Unlock()
+
+		deliver(data)
+	}
+}
+
+// settle 等待推送平稳（Debounce 内没有新的推送），分发器关闭时返回 false
+func (d *dispatcher) settle(q *dispatchQueue) bool {
+	if d.debounce <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d.debounce)
+	defer timer.Stop()
+	for {
+		d.mu.Lock()
+		seq := q.seq
+		d.mu.Unlock()
+
+		select {
+		case <-d.done:
+			return false
+		case <-timer.C:
+		}
+
+		d.mu.Lock()
+		settled := q.seq == seq
+		d.mu.Unlock()
+		if settled {
+			return true
+		}
+		timer.Reset(d.debounce)
+	}
+}
+
+// close 丢弃尚未投递的推送，等待正在执行的回调结束
+func (d *dispatcher) close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	close(d.done)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// safeCallback 执行回调并恢复panic，返回回调拒绝推送的原因，panic 视为拒绝
+func (c *NacosClient) safeCallback(dataId, group string, callback func(string) error, data string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("回调发生panic: %v", r)
+			stack := debug.Stack()
+			c.metrics.callbackPanic(dataId, group)
+			c.clientLogger().Error("监听回调发生panic",
+				LogKeyGroup, group, LogKeyDataId, dataId, "panic", fmt.Sprint(r), "stack", string(stack))
+			if c.panicHandler != nil {
+				c.panicHandler(dataId, group, r, stack)
+			}
+		}
+	}()
+	return callback(data)
+}
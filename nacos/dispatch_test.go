@@ -0,0 +1,158 @@
+package nacos
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCallbackPanicIsolation(t *testing.T) {
+	audit, err := NewAuditLog(AuditConfig{File: filepath.Join(t.TempDir(), "nacos-audit.log")})
+	if err != nil {
+		t.Fatalf("NewAuditLog() error = %v", err)
+	}
+	defer audit.Close()
+
+	sdk := nacostest.NewConfigClient()
+	var recovered interface{}
+	client := newTestClient(sdk,
+		WithAuditLog(audit),
+		WithMetrics(prometheus.NewRegistry()),
+		WithPanicHandler(func(dataId, group string, r interface{}, stack []byte) {
+			recovered = r
+		}))
+	ctx := context.Background()
+
+	var received string
+	client.ListenConfig(ctx, "app.yaml", "", func(string) { panic("boom") })
+	client.ListenConfig(ctx, "app.yaml", "", func(content string) { received = content })
+
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: "a: 1"})
+
+	if recovered != "boom" {
+		t.Errorf("Expected panic handler to receive boom, got %v", recovered)
+	}
+	if received != "a: 1" {
+		t.Errorf("Expected other callbacks to still run, got %q", received)
+	}
+	if v := testutil.ToFloat64(client.metrics.panics.WithLabelValues("DEFAULT_GROUP", "app.yaml")); v != 1 {
+		t.Errorf("Expected 1 recorded panic, got %v", v)
+	}
+	// panic 的回调视为拒绝了该推送
+	records, err := audit.Query(AuditQuery{DataId: "app.yaml"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Status != AuditRejected || !strings.Contains(records[0].Reason, "boom") {
+		t.Errorf("Expected the push to be audited as rejected, got %+v", records)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	client := newTestClient(sdk, WithDispatcher(DispatcherOptions{}))
+	defer client.Close()
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var slow []string
+	fast := make(chan string, 1)
+
+	client.ListenConfig(ctx, "slow.yaml", "", func(content string) {
+		<-release
+		mu.Lock()
+		slow = append(slow, content)
+		mu.Unlock()
+	})
+	client.ListenConfig(ctx, "fast.yaml", "", func(content string) { fast <- content })
+
+	publish := func(dataId, content string) {
+		sdk.PublishConfig(vo.ConfigParam{DataId: dataId, Group: "DEFAULT_GROUP", Content: content})
+	}
+	for i := 1; i <= 5; i++ {
+		publish("slow.yaml", string(rune('0'+i)))
+	}
+
+	// 慢回调不阻塞推送goroutine和其他配置
+	publish("fast.yaml", "x")
+	select {
+	case got := <-fast:
+		if got != "x" {
+			t.Errorf("Expected x, got %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected fast.yaml to be delivered while slow.yaml is blocked")
+	}
+
+	close(release)
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(slow) > 0 && slow[len(slow)-1] == "5"
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	// 第一次推送正在执行时到达的推送合并为最新的一次
+	if len(slow) > 2 {
+		t.Errorf("Expected pushes to be coalesced, got %v", slow)
+	}
+}
+
+func TestDispatcherDebounce(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	client := newTestClient(sdk, WithDispatcher(DispatcherOptions{Debounce: 50 * time.Millisecond}))
+	defer client.Close()
+
+	received := make(chan string, 10)
+	client.ListenConfig(context.Background(), "app.yaml", "", func(content string) { received <- content })
+	publish := func(content string) {
+		sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: content})
+	}
+
+	for _, content := range []string{"a: 1", "a: 2", "a: 3"} {
+		publish(content)
+	}
+	if got := waitReceived(t, received); got != "a: 3" {
+		t.Errorf("Expected burst to be delivered with the latest content, got %q", got)
+	}
+
+	// 同一配置按顺序投递，突发推送若被拆成多次投递会先于这次推送出现
+	publish("a: 4")
+	if got := waitReceived(t, received); got != "a: 4" {
+		t.Errorf("Expected burst to be delivered once, got extra delivery %q", got)
+	}
+}
+
+// waitReceived 等待回调收到一次推送，超时后测试失败
+func waitReceived(t *testing.T, received <-chan string) string {
+	t.Helper()
+	select {
+	case content := <-received:
+		return content
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for delivery")
+		return ""
+	}
+}
+
+// waitFor 等待条件成立，超时后测试失败
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
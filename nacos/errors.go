@@ -1,6 +1,7 @@
 package nacos
 
 import (
+	"errors"
 	"fmt"
 	"net"
 )
@@ -31,10 +32,18 @@ var (
 	ErrConfigLoadFailed     = &NacosError{Code: "CONFIG_LOAD_FAILED", Message: "配置加载失败"}
 	ErrConfigValidateFailed = &NacosError{Code: "CONFIG_VALIDATE_FAILED", Message: "配置验证失败"}
 
+	// 加解密相关错误
+	ErrEncryptFailed = &NacosError{Code: "CONFIG_ENCRYPT_FAILED", Message: "配置加密失败"}
+	ErrDecryptFailed = &NacosError{Code: "CONFIG_DECRYPT_FAILED", Message: "配置解密失败"}
+
+	// 占位符相关错误
+	ErrPlaceholderUnresolved = &NacosError{Code: "PLACEHOLDER_UNRESOLVED", Message: "占位符无法解析"}
+
 	// 客户端相关错误
 	ErrClientNotInit    = &NacosError{Code: "CLIENT_NOT_INIT", Message: "客户端未初始化"}
 	ErrClientInitFailed = &NacosError{Code: "CLIENT_INIT_FAILED", Message: "客户端初始化失败"}
 	ErrClientConnection = &NacosError{Code: "CLIENT_CONNECTION", Message: "客户端连接失败"}
+	ErrClientNotFound   = &NacosError{Code: "CLIENT_NOT_FOUND", Message: "客户端不存在"}
 
 	// 网络相关错误
 	ErrNetworkTimeout     = &NacosError{Code: "NETWORK_TIMEOUT", Message: "网络超时"}
@@ -57,6 +66,15 @@ func NewNacosError(code, message string, err error) *NacosError {
 	}
 }
 
+// ErrorCode 返回错误链中第一个NacosError的错误码，不存在时返回空字符串
+func ErrorCode(err error) string {
+	var nacosErr *NacosError
+	if errors.As(err, &nacosErr) {
+		return nacosErr.Code
+	}
+	return ""
+}
+
 // IsNetworkError 检查是否为网络错误
 func IsNetworkError(err error) bool {
 	if err == nil {
@@ -87,7 +105,8 @@ func IsConfigError(err error) bool {
 
 	if nacosErr, ok := err.(*NacosError); ok {
 		switch nacosErr.Code {
-		case "CONFIG_NOT_FOUND", "CONFIG_INVALID", "CONFIG_LOAD_FAILED", "CONFIG_VALIDATE_FAILED":
+		case "CONFIG_NOT_FOUND", "CONFIG_INVALID", "CONFIG_LOAD_FAILED", "CONFIG_VALIDATE_FAILED",
+			"CONFIG_ENCRYPT_FAILED", "CONFIG_DECRYPT_FAILED", "PLACEHOLDER_UNRESOLVED":
 			return true
 		}
 	}
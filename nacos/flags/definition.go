@@ -0,0 +1,324 @@
+package flags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// 条件运算符
+const (
+	OpEq         = "eq"
+	OpNeq        = "neq"
+	OpIn         = "in"
+	OpNotIn      = "not_in"
+	OpContains   = "contains"
+	OpStartsWith = "starts_with"
+	OpEndsWith   = "ends_with"
+	OpMatches    = "matches"
+	OpGt         = "gt"
+	OpGte        = "gte"
+	OpLt         = "lt"
+	OpLte        = "lte"
+)
+
+// 布尔开关的默认变体
+const (
+	VariantOn  = "on"
+	VariantOff = "off"
+)
+
+// Definition 开关定义文件，支持JSON与YAML
+//
+//	{
+//	  "flags": {
+//	    "new-checkout": {
+//	      "enabled": true,
+//	      "rules": [
+//	        {"conditions": [{"attribute": "country", "operator": "in", "values": ["CN"]}], "variant": "on"}
+//	      ],
+//	      "rollout": [{"variant": "on", "weight": 10}, {"variant": "off", "weight": 90}]
+//	    }
+//	  }
+//	}
+type Definition struct {
+	Flags map[string]FlagDefinition `json:"flags" yaml:"flags"`
+}
+
+// FlagDefinition 单个开关
+//
+// Variants 为空时为布尔开关，变体 on/off 分别对应 true/false。
+// 求值顺序：未启用时返回 OffVariant；按顺序匹配 Rules，命中第一条规则；
+// 都未命中时使用 Rollout 按比例分配，没有 Rollout 时返回 DefaultVariant
+type FlagDefinition struct {
+	Enabled        bool                   `json:"enabled" yaml:"enabled"`
+	Description    string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Variants       map[string]interface{} `json:"variants,omitempty" yaml:"variants,omitempty"`
+	DefaultVariant string                 `json:"default,omitempty" yaml:"default,omitempty"`
+	OffVariant     string                 `json:"off,omitempty" yaml:"off,omitempty"`
+	Rules          []Rule                 `json:"rules,omitempty" yaml:"rules,omitempty"`
+	Rollout        []WeightedVariant      `json:"rollout,omitempty" yaml:"rollout,omitempty"`
+	// Salt 参与分桶哈希，修改后用户会被重新分配
+	Salt string `json:"salt,omitempty" yaml:"salt,omitempty"`
+}
+
+// Rule 定向规则，Conditions 全部满足时命中，命中后返回 Variant 或按 Rollout 分配
+type Rule struct {
+	Conditions []Condition       `json:"conditions" yaml:"conditions"`
+	Variant    string            `json:"variant,omitempty" yaml:"variant,omitempty"`
+	Rollout    []WeightedVariant `json:"rollout,omitempty" yaml:"rollout,omitempty"`
+}
+
+// Condition 针对单个属性的条件，属性 key 表示 EvalContext.Key
+type Condition struct {
+	Attribute string   `json:"attribute" yaml:"attribute"`
+	Operator  string   `json:"operator" yaml:"operator"`
+	Values    []string `json:"values" yaml:"values"`
+}
+
+// WeightedVariant 按权重分配的变体，权重为非负整数，按占总权重的比例分配
+type WeightedVariant struct {
+	Variant string `json:"variant" yaml:"variant"`
+	Weight  int    `json:"weight" yaml:"weight"`
+}
+
+// Parse 解析并校验开关定义，YAML兼容JSON
+func Parse(content string) (*Definition, error) {
+	def, _, err := parse(content)
+	return def, err
+}
+
+// parseRuleSet 解析并编译开关定义
+func parseRuleSet(content string) (*ruleSet, error) {
+	_, set, err := parse(content)
+	return set, err
+}
+
+func parse(content string) (*Definition, *ruleSet, error) {
+	var def Definition
+	if strings.TrimSpace(content) != "" {
+		if err := yaml.Unmarshal([]byte(content), &def); err != nil {
+			return nil, nil, fmt.Errorf("解析开关定义失败: %w", err)
+		}
+	}
+	set, err := compile(&def)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &def, set, nil
+}
+
+// ruleSet 编译后的开关定义，加载后只读
+type ruleSet struct {
+	flags map[string]*flag
+}
+
+type flag struct {
+	key        string
+	enabled    bool
+	variants   map[string]interface{}
+	defaultVar string
+	offVar     string
+	rules      []rule
+	rollout    *rollout
+	salt       string
+}
+
+type rule struct {
+	conditions []condition
+	variant    string
+	rollout    *rollout
+}
+
+type condition struct {
+	attribute string
+	operator  string
+	values    []string
+	numbers   []float64
+	pattern   *regexp.Regexp
+}
+
+type rollout struct {
+	variants []string
+	// bounds 累计权重，bounds[i] 为前 i+1 个变体的权重和
+	bounds []uint32
+	total  uint32
+}
+
+// compile 校验并编译开关定义
+func compile(def *Definition) (*ruleSet, error) {
+	set := &ruleSet{flags: make(map[string]*flag, len(def.Flags))}
+
+	keys := make([]string, 0, len(def.Flags))
+	for key := range def.Flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		f, err := compileFlag(key, def.Flags[key])
+		if err != nil {
+			return nil, fmt.Errorf("开关 %s: %w", key, err)
+		}
+		set.flags[key] = f
+	}
+	return set, nil
+}
+
+func compileFlag(key string, def FlagDefinition) (*flag, error) {
+	f := &flag{
+		key:        key,
+		enabled:    def.Enabled,
+		variants:   def.Variants,
+		defaultVar: def.DefaultVariant,
+		offVar:     def.OffVariant,
+		salt:       def.Salt,
+	}
+
+	if len(f.variants) == 0 {
+		f.variants = map[string]interface{}{VariantOn: true, VariantOff: false}
+		if f.defaultVar == "" {
+			f.defaultVar = VariantOn
+		}
+		if f.offVar == "" {
+			f.offVar = VariantOff
+		}
+	}
+	if f.defaultVar == "" || f.offVar == "" {
+		return nil, fmt.Errorf("多值开关必须指定 default 与 off 变体")
+	}
+	if err := f.checkVariant(f.defaultVar); err != nil {
+		return nil, err
+	}
+	if err := f.checkVariant(f.offVar); err != nil {
+		return nil, err
+	}
+
+	for i, r := range def.Rules {
+		compiled, err := f.compileRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("规则 %d: %w", i, err)
+		}
+		f.rules = append(f.rules, compiled)
+	}
+
+	if len(def.Rollout) > 0 {
+		ro, err := f.compileRollout(def.Rollout)
+		if err != nil {
+			return nil, err
+		}
+		f.rollout = ro
+	}
+	return f, nil
+}
+
+func (f *flag) checkVariant(variant string) error {
+	if _, ok := f.variants[variant]; !ok {
+		return fmt.Errorf("变体 %s 未定义", variant)
+	}
+	return nil
+}
+
+func (f *flag) compileRule(r Rule) (rule, error) {
+	compiled := rule{variant: r.Variant}
+	if (r.Variant == "") == (len(r.Rollout) == 0) {
+		return rule{}, fmt.Errorf("variant 与 rollout 必须且只能指定一个")
+	}
+	if r.Variant != "" {
+		if err := f.checkVariant(r.Variant); err != nil {
+			return rule{}, err
+		}
+	} else {
+		ro, err := f.compileRollout(r.Rollout)
+		if err != nil {
+			return rule{}, err
+		}
+		compiled.rollout = ro
+	}
+
+	for _, c := range r.Conditions {
+		cond, err := compileCondition(c)
+		if err != nil {
+			return rule{}, err
+		}
+		compiled.conditions = append(compiled.conditions, cond)
+	}
+	return compiled, nil
+}
+
+func compileCondition(c Condition) (condition, error) {
+	cond := condition{attribute: c.Attribute, operator: c.Operator, values: c.Values}
+	if c.Attribute == "" {
+		return condition{}, fmt.Errorf("条件未指定属性")
+	}
+	if len(c.Values) == 0 {
+		return condition{}, fmt.Errorf("条件 %s 未指定值", c.Attribute)
+	}
+
+	switch c.Operator {
+	case OpEq, OpNeq, OpIn, OpNotIn, OpContains, OpStartsWith, OpEndsWith:
+	case OpMatches:
+		pattern, err := regexp.Compile(c.Values[0])
+		if err != nil {
+			return condition{}, fmt.Errorf("条件 %s 的正则无效: %w", c.Attribute, err)
+		}
+		cond.pattern = pattern
+	case OpGt, OpGte, OpLt, OpLte:
+		for _, v := range c.Values {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return condition{}, fmt.Errorf("条件 %s 的值不是数字: %s", c.Attribute, v)
+			}
+			cond.numbers = append(cond.numbers, n)
+		}
+	default:
+		return condition{}, fmt.Errorf("不支持的运算符: %s", c.Operator)
+	}
+	return cond, nil
+}
+
+func (f *flag) compileRollout(weights []WeightedVariant) (*rollout, error) {
+	ro := &rollout{}
+	for _, w := range weights {
+		if w.Weight < 0 {
+			return nil, fmt.Errorf("变体 %s 的权重不能为负数", w.Variant)
+		}
+		if err := f.checkVariant(w.Variant); err != nil {
+			return nil, err
+		}
+		ro.total += uint32(w.Weight)
+		ro.variants = append(ro.variants, w.Variant)
+		ro.bounds = append(ro.bounds, ro.total)
+	}
+	if ro.total == 0 {
+		return nil, fmt.Errorf("rollout 的总权重必须大于0")
+	}
+	return ro, nil
+}
+
+// pick 根据哈希值选择变体，同一用户在权重不变时总是得到同一变体
+func (r *rollout) pick(hash uint32) string {
+	bucket := hash % r.total
+	for i, bound := range r.bounds {
+		if bucket < bound {
+			return r.variants[i]
+		}
+	}
+	return r.variants[len(r.variants)-1]
+}
+
+// bucketHash 计算用户在开关上的分桶哈希
+func bucketHash(flagKey, salt, userKey string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(flagKey))
+	h.Write([]byte{':'})
+	h.Write([]byte(salt))
+	h.Write([]byte{':'})
+	h.Write([]byte(userKey))
+	return h.Sum32()
+}
@@ -0,0 +1,312 @@
+// Package flags 基于配置中心的功能开关
+//
+// 开关定义存放在一个 dataId 中（JSON或YAML，格式见 Definition），Engine 通过 nacos.ConfigSource
+// 加载并监听该 dataId，变更后整体替换已编译的规则，求值过程只做一次原子读，不加锁。
+// 支持布尔与多值开关、按属性定向的规则，以及基于用户标识粘性哈希的百分比灰度。
+package flags
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fuyx123/common-package/nacos"
+)
+
+// 求值原因
+const (
+	ReasonDisabled       = "DISABLED"
+	ReasonTargetingMatch = "TARGETING_MATCH"
+	ReasonRollout        = "ROLLOUT"
+	ReasonDefault        = "DEFAULT"
+	ReasonFlagNotFound   = "FLAG_NOT_FOUND"
+)
+
+// EvalContext 求值上下文
+type EvalContext struct {
+	// Key 用户标识，用于百分比灰度的粘性分桶，为空时灰度规则不生效
+	Key string
+	// Attributes 用于定向规则的属性
+	Attributes map[string]string
+}
+
+// attribute 返回属性值，key 对应 EvalContext.Key
+func (c EvalContext) attribute(name string) (string, bool) {
+	if name == "key" {
+		return c.Key, c.Key != ""
+	}
+	v, ok := c.Attributes[name]
+	return v, ok
+}
+
+// Evaluation 求值结果
+type Evaluation struct {
+	Flag    string
+	Variant string
+	Value   interface{}
+	Reason  string
+}
+
+// Engine 开关求值引擎
+type Engine struct {
+	source nacos.ConfigSource
+	dataId string
+	group  string
+	logger nacos.Logger
+
+	rules  atomic.Pointer[ruleSet]
+	cancel func()
+
+	// mu 串行化推送与首次加载，pushed 表示已应用过推送，此后首次读取的结果已过期
+	mu     sync.Mutex
+	pushed bool
+}
+
+// Option 引擎可选配置
+type Option func(*Engine)
+
+// WithLogger 设置日志，未设置时使用 nacos.DefaultLogger()
+func WithLogger(logger nacos.Logger) Option {
+	return func(e *Engine) {
+		e.logger = logger
+	}
+}
+
+// New 加载开关定义并监听变化
+// 首次加载失败返回错误；之后推送的定义无效时保留当前规则并记录日志
+func New(ctx context.Context, source nacos.ConfigSource, dataId, group string, opts ...Option) (*Engine, error) {
+	e := &Engine{source: source, dataId: dataId, group: group}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	// 先监听再读取，避免丢失两者之间的推送；读取期间已应用推送时丢弃读取结果
+	cancel, err := source.Watch(ctx, dataId, group, e.reload)
+	if err != nil {
+		return nil, err
+	}
+	e.cancel = cancel
+
+	content, err := source.Get(ctx, dataId, group)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.pushed {
+		return e, nil
+	}
+	if err := e.Load(content); err != nil {
+		cancel()
+		return nil, err
+	}
+	return e, nil
+}
+
+// NewStatic 使用固定的开关定义创建引擎，不监听配置，适用于测试
+func NewStatic(content string) (*Engine, error) {
+	e := &Engine{}
+	if err := e.Load(content); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Engine) log() nacos.Logger {
+	if e.logger != nil {
+		return e.logger
+	}
+	return nacos.DefaultLogger()
+}
+
+// Load 解析开关定义并替换当前规则
+func (e *Engine) Load(content string) error {
+	set, err := parseRuleSet(content)
+	if err != nil {
+		return nacos.NewNacosError(nacos.ErrConfigInvalid.Code, fmt.Sprintf("开关定义无效 [DataId: %s, Group: %s]", e.dataId, e.group), err)
+	}
+	e.rules.Store(set)
+	return nil
+}
+
+// reload 处理推送
+func (e *Engine) reload(content string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.Load(content); err != nil {
+		e.log().Warn("开关定义无效，继续使用当前规则",
+			nacos.LogKeyGroup, e.group, nacos.LogKeyDataId, e.dataId, nacos.LogKeyError, err.Error())
+		return
+	}
+	e.pushed = true
+	e.log().Info("开关定义已更新", nacos.LogKeyGroup, e.group, nacos.LogKeyDataId, e.dataId)
+}
+
+// Flags 返回当前定义的全部开关名
+func (e *Engine) Flags() []string {
+	set := e.rules.Load()
+	if set == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(set.flags))
+	for key := range set.flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Evaluate 对开关求值
+func (e *Engine) Evaluate(flagKey string, ctx EvalContext) Evaluation {
+	set := e.rules.Load()
+	if set == nil {
+		return Evaluation{Flag: flagKey, Reason: ReasonFlagNotFound}
+	}
+	f, ok := set.flags[flagKey]
+	if !ok {
+		return Evaluation{Flag: flagKey, Reason: ReasonFlagNotFound}
+	}
+	variant, reason := f.evaluate(ctx)
+	return Evaluation{Flag: flagKey, Variant: variant, Value: f.variants[variant], Reason: reason}
+}
+
+// Bool 对布尔开关求值，开关不存在或值不是布尔时返回 def
+func (e *Engine) Bool(flagKey string, ctx EvalContext, def bool) bool {
+	if v, ok := e.Evaluate(flagKey, ctx).Value.(bool); ok {
+		return v
+	}
+	return def
+}
+
+// String 对开关求值并返回字符串值，开关不存在或值不是字符串时返回 def
+func (e *Engine) String(flagKey string, ctx EvalContext, def string) string {
+	if v, ok := e.Evaluate(flagKey, ctx).Value.(string); ok {
+		return v
+	}
+	return def
+}
+
+// Variant 返回命中的变体名，开关不存在时返回 def
+func (e *Engine) Variant(flagKey string, ctx EvalContext, def string) string {
+	result := e.Evaluate(flagKey, ctx)
+	if result.Reason == ReasonFlagNotFound {
+		return def
+	}
+	return result.Variant
+}
+
+// Close 停止监听
+func (e *Engine) Close() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+}
+
+// Validator 返回校验开关定义的校验器，可注册到 NacosClient 以拒绝发布无效定义
+func Validator() nacos.Validator {
+	return nacos.ValidatorFunc(func(dataId, content string) error {
+		_, err := Parse(content)
+		return err
+	})
+}
+
+// evaluate 返回命中的变体与原因
+func (f *flag) evaluate(ctx EvalContext) (string, string) {
+	if !f.enabled {
+		return f.offVar, ReasonDisabled
+	}
+
+	for _, r := range f.rules {
+		if !r.matches(ctx) {
+			continue
+		}
+		if r.rollout == nil {
+			return r.variant, ReasonTargetingMatch
+		}
+		if ctx.Key != "" {
+			return r.rollout.pick(bucketHash(f.key, f.salt, ctx.Key)), ReasonTargetingMatch
+		}
+	}
+
+	if f.rollout != nil && ctx.Key != "" {
+		return f.rollout.pick(bucketHash(f.key, f.salt, ctx.Key)), ReasonRollout
+	}
+	return f.defaultVar, ReasonDefault
+}
+
+// matches 全部条件满足时命中，没有条件的规则总是命中
+func (r rule) matches(ctx EvalContext) bool {
+	for _, c := range r.conditions {
+		if !c.matches(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c condition) matches(ctx EvalContext) bool {
+	value, ok := ctx.attribute(c.attribute)
+	if !ok {
+		// 属性缺失时只有否定运算符成立
+		return c.operator == OpNeq || c.operator == OpNotIn
+	}
+
+	switch c.operator {
+	case OpEq:
+		return value == c.values[0]
+	case OpNeq:
+		return value != c.values[0]
+	case OpIn:
+		return containsString(c.values, value)
+	case OpNotIn:
+		return !containsString(c.values, value)
+	case OpContains:
+		return anyString(c.values, func(v string) bool { return strings.Contains(value, v) })
+	case OpStartsWith:
+		return anyString(c.values, func(v string) bool { return strings.HasPrefix(value, v) })
+	case OpEndsWith:
+		return anyString(c.values, func(v string) bool { return strings.HasSuffix(value, v) })
+	case OpMatches:
+		return c.pattern.MatchString(value)
+	case OpGt, OpGte, OpLt, OpLte:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		target := c.numbers[0]
+		switch c.operator {
+		case OpGt:
+			return n > target
+		case OpGte:
+			return n >= target
+		case OpLt:
+			return n < target
+		default:
+			return n <= target
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func anyString(values []string, fn func(string) bool) bool {
+	for _, v := range values {
+		if fn(v) {
+			return true
+		}
+	}
+	return false
+}
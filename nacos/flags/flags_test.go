@@ -0,0 +1,174 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos"
+)
+
+const testDefinition = `{
+  "flags": {
+    "new-checkout": {
+      "enabled": true,
+      "default": "off",
+      "rules": [
+        {"conditions": [{"attribute": "country", "operator": "in", "values": ["CN", "SG"]},
+                        {"attribute": "age", "operator": "gte", "values": ["18"]}], "variant": "on"},
+        {"conditions": [{"attribute": "email", "operator": "ends_with", "values": ["@example.com"]}], "variant": "on"}
+      ],
+      "rollout": [{"variant": "on", "weight": 20}, {"variant": "off", "weight": 80}]
+    },
+    "theme": {
+      "enabled": true,
+      "variants": {"light": "#fff", "dark": "#000"},
+      "default": "light",
+      "off": "light",
+      "rules": [{"conditions": [{"attribute": "key", "operator": "matches", "values": ["^beta-"]}], "variant": "dark"}]
+    },
+    "kill-switch": {"enabled": false}
+  }
+}`
+
+func TestEvaluate(t *testing.T) {
+	engine, err := NewStatic(testDefinition)
+	if err != nil {
+		t.Fatalf("NewStatic() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		flag    string
+		ctx     EvalContext
+		variant string
+		reason  string
+	}{
+		{"targeting match", "new-checkout", EvalContext{Key: "u1", Attributes: map[string]string{"country": "CN", "age": "20"}}, "on", ReasonTargetingMatch},
+		{"second rule", "new-checkout", EvalContext{Attributes: map[string]string{"email": "a@example.com"}}, "on", ReasonTargetingMatch},
+		{"partial conditions", "new-checkout", EvalContext{Attributes: map[string]string{"country": "CN", "age": "16"}}, "off", ReasonDefault},
+		{"rollout without key", "new-checkout", EvalContext{}, "off", ReasonDefault},
+		{"multivariate", "theme", EvalContext{Key: "beta-42"}, "dark", ReasonTargetingMatch},
+		{"multivariate default", "theme", EvalContext{Key: "u1"}, "light", ReasonDefault},
+		{"disabled", "kill-switch", EvalContext{Key: "u1"}, "off", ReasonDisabled},
+		{"not found", "missing", EvalContext{}, "", ReasonFlagNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := engine.Evaluate(tt.flag, tt.ctx)
+			if result.Variant != tt.variant || result.Reason != tt.reason {
+				t.Errorf("Evaluate() = %s/%s, want %s/%s", result.Variant, result.Reason, tt.variant, tt.reason)
+			}
+		})
+	}
+
+	if v := engine.String("theme", EvalContext{Key: "beta-1"}, ""); v != "#000" {
+		t.Errorf("Expected #000, got %s", v)
+	}
+	if !engine.Bool("missing", EvalContext{}, true) {
+		t.Error("Expected default for missing flag")
+	}
+}
+
+func TestRolloutIsStickyAndProportional(t *testing.T) {
+	engine, err := NewStatic(testDefinition)
+	if err != nil {
+		t.Fatalf("NewStatic() error = %v", err)
+	}
+
+	on := 0
+	const users = 10000
+	for i := 0; i < users; i++ {
+		ctx := EvalContext{Key: fmt.Sprintf("user-%d", i)}
+		first := engine.Bool("new-checkout", ctx, false)
+		if engine.Bool("new-checkout", ctx, false) != first {
+			t.Fatalf("Expected sticky result for %s", ctx.Key)
+		}
+		if first {
+			on++
+		}
+	}
+	if ratio := float64(on) / users; ratio < 0.17 || ratio > 0.23 {
+		t.Errorf("Expected about 20%% of users enabled, got %.2f", ratio)
+	}
+}
+
+func TestHotReload(t *testing.T) {
+	source := nacos.NewMemorySource()
+	ctx := context.Background()
+	if err := source.Publish(ctx, "flags.json", "", `{"flags": {"beta": {"enabled": false}}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := New(ctx, source, "flags.json", "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer engine.Close()
+
+	if engine.Bool("beta", EvalContext{}, true) {
+		t.Fatal("Expected beta to be disabled")
+	}
+
+	source.Publish(ctx, "flags.json", "", "flags:\n  beta:\n    enabled: true\n")
+	if !engine.Bool("beta", EvalContext{}, false) {
+		t.Fatal("Expected beta to be enabled after reload")
+	}
+
+	// 无效定义不会替换当前规则
+	source.Publish(ctx, "flags.json", "", `{"flags": {"beta": {"enabled": true, "default": "missing"}}}`)
+	if !engine.Bool("beta", EvalContext{}, false) {
+		t.Error("Expected invalid definition to be ignored")
+	}
+}
+
+// racingSource 在返回读取结果前推送更新的内容，模拟读取期间到达的推送
+type racingSource struct {
+	*nacos.MemorySource
+	newer string
+}
+
+func (s *racingSource) Get(ctx context.Context, dataId, group string) (string, error) {
+	content, err := s.MemorySource.Get(ctx, dataId, group)
+	if err == nil {
+		err = s.Publish(ctx, dataId, group, s.newer)
+	}
+	return content, err
+}
+
+func TestPushDuringInitialLoad(t *testing.T) {
+	source := &racingSource{MemorySource: nacos.NewMemorySource(), newer: `{"flags": {"beta": {"enabled": true}}}`}
+	ctx := context.Background()
+	if err := source.Publish(ctx, "flags.json", "", `{"flags": {"beta": {"enabled": false}}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := New(ctx, source, "flags.json", "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer engine.Close()
+	if !engine.Bool("beta", EvalContext{}, false) {
+		t.Error("Expected the push during the initial load not to be overwritten")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"syntax", `{"flags": `},
+		{"unknown variant", `{"flags": {"a": {"enabled": true, "rules": [{"variant": "maybe"}]}}}`},
+		{"unknown operator", `{"flags": {"a": {"enabled": true, "rules": [{"conditions": [{"attribute": "x", "operator": "like", "values": ["1"]}], "variant": "on"}]}}}`},
+		{"zero weight", `{"flags": {"a": {"enabled": true, "rollout": [{"variant": "on", "weight": 0}]}}}`},
+		{"multivariate without off", `{"flags": {"a": {"enabled": true, "variants": {"x": 1}, "default": "x"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validator().Validate("flags.json", tt.content); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 健康状态
+const (
+	HealthUp       = "UP"
+	HealthDown     = "DOWN"
+	HealthDegraded = "DEGRADED"
+)
+
+// defaultHealthTimeout 健康检查的默认超时时间
+const defaultHealthTimeout = 3 * time.Second
+
+// HealthStatus 客户端健康状态
+type HealthStatus struct {
+	Status    string `json:"status"`
+	Server    string `json:"server"`
+	Namespace string `json:"namespace"`
+	// Latency 探测耗时，JSON中以毫秒输出为 latencyMs
+	Latency          time.Duration    `json:"-"`
+	Error            string           `json:"error,omitempty"`
+	ServingFromCache bool             `json:"servingFromCache"`
+	LastSuccess      time.Time        `json:"lastSuccess,omitzero"`
+	Listeners        []ListenerStatus `json:"listeners"`
+	CheckedAt        time.Time        `json:"checkedAt"`
+}
+
+// ListenerStatus 单个监听的状态
+type ListenerStatus struct {
+	DataId    string    `json:"dataId"`
+	Group     string    `json:"group"`
+	Callbacks int       `json:"callbacks"`
+	LastPush  time.Time `json:"lastPush,omitzero"`
+}
+
+// MarshalJSON 将 Latency 输出为毫秒数 latencyMs
+func (s HealthStatus) MarshalJSON() ([]byte, error) {
+	type status HealthStatus
+	return json.Marshal(struct {
+		status
+		LatencyMs float64 `json:"latencyMs"`
+	}{status(s), float64(s.Latency.Microseconds()) / 1000})
+}
+
+// HealthProbe 探测Nacos服务端是否可用
+type HealthProbe func(ctx context.Context) error
+
+// WithHealthProbe 自定义服务端探测方式，默认请求服务端的 /v1/console/health/readiness 接口
+func WithHealthProbe(probe HealthProbe) Option {
+	return func(c *NacosClient) {
+		c.healthProbe = probe
+	}
+}
+
+// healthState 健康检查相关的运行时状态
+type healthState struct {
+	mu          sync.RWMutex
+	lastSuccess time.Time
+}
+
+// markSuccess 记录最近一次成功获取配置或收到推送的时间
+func (h *healthState) markSuccess() {
+	h.mu.Lock()
+	h.lastSuccess = time.Now()
+	h.mu.Unlock()
+}
+
+func (h *healthState) lastSuccessAt() time.Time {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.lastSuccess
+}
+
+// HealthCheck 检查Nacos服务端状态，返回延迟与监听状态
+// 服务端不可用但此前成功获取过配置时 ServingFromCache 为 true
+func (c *NacosClient) HealthCheck(ctx context.Context) HealthStatus {
+	if c == nil || c.client == nil {
+		return HealthStatus{Status: HealthDown, Error: "Nacos客户端未初始化", CheckedAt: time.Now()}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultHealthTimeout)
+		defer cancel()
+	}
+
+	probe := c.healthProbe
+	if probe == nil {
+		probe = c.httpHealthProbe
+	}
+
+	start := time.Now()
+	err := probe(ctx)
+	status := HealthStatus{
+		Status:      HealthUp,
+		Server:      c.config.GetServerURL(),
+		Namespace:   c.config.Nacos.Namespace,
+		Latency:     time.Since(start),
+		LastSuccess: c.health.lastSuccessAt(),
+		Listeners:   c.listenerStatuses(),
+		CheckedAt:   time.Now(),
+	}
+	if err != nil {
+		status.Status = HealthDown
+		status.Error = err.Error()
+		status.ServingFromCache = !status.LastSuccess.IsZero()
+	}
+	return status
+}
+
+// httpHealthProbe 请求服务端的就绪检查接口
+func (c *NacosClient) httpHealthProbe(ctx context.Context) error {
+	url := c.config.GetServerURL() + "/v1/console/health/readiness"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return NewNacosError(ErrServerUnavailable.Code, "Nacos服务端不可用", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return NewNacosError(ErrServerUnavailable.Code, fmt.Sprintf("Nacos服务端未就绪，状态码: %d", resp.StatusCode), nil)
+	}
+	return nil
+}
+
+// listenerStatuses 返回当前全部监听的状态
+func (c *NacosClient) listenerStatuses() []ListenerStatus {
+	c.listenMu.Lock()
+	defer c.listenMu.Unlock()
+
+	statuses := make([]ListenerStatus, 0, len(c.listeners))
+	for _, entry := range c.listeners {
+		statuses = append(statuses, ListenerStatus{
+			DataId:    entry.dataId,
+			Group:     entry.group,
+			Callbacks: len(entry.callbacks),
+			LastPush:  entry.lastPush,
+		})
+	}
+	return statuses
+}
+
+// HealthHandlerOptions 健康检查HTTP处理器配置
+type HealthHandlerOptions struct {
+	// Critical 为 true 时Nacos不可用则 /readyz 返回503；
+	// 为 false 时只要此前成功获取过配置（使用缓存的配置继续服务）就返回200，状态为 DEGRADED
+	Critical bool
+	// Timeout 单次检查的超时时间，默认3秒
+	Timeout time.Duration
+}
+
+// NewHealthzHandler 创建存活检查处理器(/healthz)，客户端已初始化即返回200
+func NewHealthzHandler(c *NacosClient) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c == nil || c.client == nil {
+			writeHealth(w, http.StatusServiceUnavailable, HealthStatus{Status: HealthDown, Error: "Nacos客户端未初始化", CheckedAt: time.Now()})
+			return
+		}
+		writeHealth(w, http.StatusOK, HealthStatus{Status: HealthUp, CheckedAt: time.Now()})
+	})
+}
+
+// NewReadyzHandler 创建就绪检查处理器(/readyz)，根据Nacos连通性返回状态
+func NewReadyzHandler(c *NacosClient, opts HealthHandlerOptions) http.Handler {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		status := c.HealthCheck(ctx)
+		switch {
+		case status.Status == HealthUp:
+			writeHealth(w, http.StatusOK, status)
+		case !opts.Critical && status.ServingFromCache:
+			status.Status = HealthDegraded
+			writeHealth(w, http.StatusOK, status)
+		default:
+			writeHealth(w, http.StatusServiceUnavailable, status)
+		}
+	})
+}
+
+func writeHealth(w http.ResponseWriter, code int, status HealthStatus) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,137 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+func TestHealthCheck(t *testing.T) {
+	var probeErr error
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("app.yaml", "DEFAULT_GROUP", "a: 1")
+	client := newTestClient(sdk, WithHealthProbe(func(ctx context.Context) error { return probeErr }))
+	ctx := context.Background()
+
+	if err := client.ListenConfig(ctx, "app.yaml", "DEFAULT_GROUP", func(string) {}); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+
+	status := client.HealthCheck(ctx)
+	if status.Status != HealthUp {
+		t.Errorf("Expected status %s, got %s", HealthUp, status.Status)
+	}
+	if len(status.Listeners) != 1 || status.Listeners[0].Callbacks != 1 || !status.Listeners[0].LastPush.IsZero() {
+		t.Errorf("Unexpected listener state: %+v", status.Listeners)
+	}
+
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: "a: 2"})
+	status = client.HealthCheck(ctx)
+	if status.Listeners[0].LastPush.IsZero() {
+		t.Error("Expected last push time to be recorded")
+	}
+
+	probeErr = errors.New("connection refused")
+	status = client.HealthCheck(ctx)
+	if status.Status != HealthDown || status.Error == "" {
+		t.Errorf("Expected status %s with error, got %+v", HealthDown, status)
+	}
+	if !status.ServingFromCache {
+		t.Error("Expected ServingFromCache after a successful push")
+	}
+
+	probeErr = nil
+	// nil ctx 按 context.Background() 处理
+	if status := client.HealthCheck(nil); status.Status != HealthUp {
+		t.Errorf("Expected status %s with nil context, got %+v", HealthUp, status)
+	}
+}
+
+func TestHealthStatusJSON(t *testing.T) {
+	data, err := json.Marshal(HealthStatus{
+		Status:    HealthUp,
+		Latency:   1500 * time.Microsecond,
+		Listeners: []ListenerStatus{{DataId: "app.yaml", Group: "DEFAULT_GROUP", Callbacks: 1}},
+		CheckedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	body := string(data)
+	if !strings.Contains(body, `"latencyMs":1.5`) || strings.Contains(body, `"latency"`) || strings.Contains(body, `"Latency"`) {
+		t.Errorf("Expected latency in milliseconds, got %s", body)
+	}
+	if strings.Contains(body, "lastSuccess") || strings.Contains(body, "lastPush") {
+		t.Errorf("Expected zero times to be omitted, got %s", body)
+	}
+	if !strings.Contains(body, `"checkedAt":"2024-01-02T03:04:05Z"`) {
+		t.Errorf("Expected other fields to be kept, got %s", body)
+	}
+}
+
+func TestHealthHandlers(t *testing.T) {
+	probeErr := errors.New("connection refused")
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("app.yaml", "DEFAULT_GROUP", "a: 1")
+	client := newTestClient(sdk, WithHealthProbe(func(ctx context.Context) error { return probeErr }))
+
+	serve := func(h http.Handler) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec
+	}
+
+	if rec := serve(NewHealthzHandler(client)); rec.Code != http.StatusOK {
+		t.Errorf("Expected /healthz 200, got %d", rec.Code)
+	}
+	if rec := serve(NewHealthzHandler(nil)); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /healthz 503 without client, got %d", rec.Code)
+	}
+
+	degraded := NewReadyzHandler(client, HealthHandlerOptions{})
+	critical := NewReadyzHandler(client, HealthHandlerOptions{Critical: true})
+
+	// 从未成功获取过配置，两种模式都未就绪
+	if rec := serve(degraded); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz 503 without cache, got %d", rec.Code)
+	}
+
+	if _, err := client.GetConfig(context.Background(), "app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		code    int
+		status  string
+	}{
+		{"degraded", degraded, http.StatusOK, HealthDegraded},
+		{"critical", critical, http.StatusServiceUnavailable, HealthDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.handler)
+			if rec.Code != tt.code {
+				t.Errorf("Expected %d, got %d", tt.code, rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), strconv.Quote(tt.status)) {
+				t.Errorf("Expected status %s in body %s", tt.status, rec.Body.String())
+			}
+		})
+	}
+
+	probeErr = nil
+	if rec := serve(critical); rec.Code != http.StatusOK {
+		t.Errorf("Expected /readyz 200 when server is up, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,236 @@
+package nacos
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+
+	sdklogger "github.com/nacos-group/nacos-sdk-go/v2/common/logger"
+	"go.uber.org/zap"
+)
+
+// 结构化日志字段名
+const (
+	LogKeyNamespace = "namespace"
+	LogKeyGroup     = "group"
+	LogKeyDataId    = "dataId"
+	LogKeyCode      = "code"
+	LogKeyError     = "error"
+	LogKeySource    = "source"
+)
+
+// Logger 结构化日志接口，keysAndValues 为交替出现的字段名和值
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+var (
+	defaultLogger   Logger = stdLogger{}
+	defaultLoggerMu sync.RWMutex
+	customLoggerSet bool
+)
+
+// SetLogger 设置包级默认日志，未通过 WithLogger 指定日志的客户端和便捷方法使用该日志
+func SetLogger(logger Logger) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	if logger == nil {
+		defaultLogger = stdLogger{}
+		customLoggerSet = false
+		return
+	}
+	defaultLogger = logger
+	customLoggerSet = true
+}
+
+// DefaultLogger 返回包级默认日志，供其他配置源复用 SetLogger 设置的日志
+func DefaultLogger() Logger {
+	return packageLogger()
+}
+
+// packageLogger 返回包级默认日志
+func packageLogger() Logger {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
+}
+
+// WithLogger 设置客户端使用的日志
+// 同时会将 nacos-sdk-go 的日志转发到该日志，SDK日志是全局的，以最后创建的客户端为准
+func WithLogger(logger Logger) Option {
+	return func(c *NacosClient) {
+		c.logger = logger
+	}
+}
+
+// clientLogger 返回客户端使用的日志
+func (c *NacosClient) clientLogger() Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return packageLogger()
+}
+
+// logFields 生成配置相关的结构化字段
+func (c *NacosClient) logFields(dataId, group string, err error, extra ...interface{}) []interface{} {
+	fields := []interface{}{
+		LogKeyNamespace, c.config.Nacos.Namespace,
+		LogKeyGroup, group,
+		LogKeyDataId, dataId,
+	}
+	fields = append(fields, errorFields(err)...)
+	return append(fields, extra...)
+}
+
+// errorFields 生成错误相关的结构化字段
+func errorFields(err error) []interface{} {
+	if err == nil {
+		return nil
+	}
+	fields := []interface{}{LogKeyError, err.Error()}
+	if code := ErrorCode(err); code != "" {
+		fields = append(fields, LogKeyCode, code)
+	}
+	return fields
+}
+
+// routeSDKLogger 将SDK日志转发到客户端日志，未设置任何自定义日志时保留SDK默认的文件日志
+func (c *NacosClient) routeSDKLogger() {
+	defaultLoggerMu.RLock()
+	custom := customLoggerSet
+	defaultLoggerMu.RUnlock()
+	if c.logger == nil && !custom {
+		return
+	}
+	sdklogger.SetLogger(&sdkLogger{logger: c.clientLogger()})
+}
+
+// stdLogger 基于标准库log的默认日志，字段以 key=value 形式追加在消息之后
+type stdLogger struct{}
+
+func (stdLogger) Debug(msg string, keysAndValues ...interface{}) {}
+
+func (l stdLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.print("INFO", msg, keysAndValues)
+}
+
+func (l stdLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.print("WARN", msg, keysAndValues)
+}
+
+func (l stdLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.print("ERROR", msg, keysAndValues)
+}
+
+func (stdLogger) print(level, msg string, keysAndValues []interface{}) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteString(" ")
+	b.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		b.WriteString(" ")
+		if i+1 < len(keysAndValues) {
+			fmt.Fprintf(&b, "%v=%v", keysAndValues[i], keysAndValues[i+1])
+		} else {
+			fmt.Fprintf(&b, "%v", keysAndValues[i])
+		}
+	}
+	log.Print(b.String())
+}
+
+// zapLogger zap适配器
+type zapLogger struct {
+	logger *zap.SugaredLogger
+}
+
+// NewZapLogger 使用zap创建日志
+func NewZapLogger(logger *zap.Logger) Logger {
+	return &zapLogger{logger: logger.WithOptions(zap.AddCallerSkip(1)).Sugar()}
+}
+
+func (l *zapLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.logger.Debugw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Infow(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.logger.Warnw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Errorw(msg, keysAndValues...)
+}
+
+// slogLogger slog适配器
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger 使用slog创建日志
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+func (l *slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.logger.Log(context.Background(), slog.LevelDebug, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Log(context.Background(), slog.LevelInfo, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.logger.Log(context.Background(), slog.LevelWarn, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Log(context.Background(), slog.LevelError, msg, keysAndValues...)
+}
+
+// sdkLogger 将nacos-sdk-go的日志转发到Logger
+type sdkLogger struct {
+	logger Logger
+}
+
+var _ sdklogger.Logger = (*sdkLogger)(nil)
+
+func (l *sdkLogger) Info(args ...interface{}) {
+	l.logger.Info(fmt.Sprint(args...), LogKeySource, "nacos-sdk")
+}
+
+func (l *sdkLogger) Warn(args ...interface{}) {
+	l.logger.Warn(fmt.Sprint(args...), LogKeySource, "nacos-sdk")
+}
+
+func (l *sdkLogger) Error(args ...interface{}) {
+	l.logger.Error(fmt.Sprint(args...), LogKeySource, "nacos-sdk")
+}
+
+func (l *sdkLogger) Debug(args ...interface{}) {
+	l.logger.Debug(fmt.Sprint(args...), LogKeySource, "nacos-sdk")
+}
+
+func (l *sdkLogger) Infof(format string, args ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, args...), LogKeySource, "nacos-sdk")
+}
+
+func (l *sdkLogger) Warnf(format string, args ...interface{}) {
+	l.logger.Warn(fmt.Sprintf(format, args...), LogKeySource, "nacos-sdk")
+}
+
+func (l *sdkLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...), LogKeySource, "nacos-sdk")
+}
+
+func (l *sdkLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, args...), LogKeySource, "nacos-sdk")
+}
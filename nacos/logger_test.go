@@ -0,0 +1,60 @@
+package nacos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+func TestClientStructuredLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	sdk := nacostest.NewConfigClient()
+	client := newTestClient(sdk, WithLogger(logger))
+	client.config.Nacos.Namespace = "dev"
+	client.RegisterValidator("app.json", "DEFAULT_GROUP", ValidatorFunc(func(dataId, content string) error {
+		return json.Unmarshal([]byte(content), new(interface{}))
+	}))
+
+	if err := client.ListenConfig(context.Background(), "app.json", "DEFAULT_GROUP", nil); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.json", Group: "DEFAULT_GROUP", Content: "{broken"})
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log entry, got %q", buf.String())
+	}
+	expected := map[string]string{
+		"level":         "WARN",
+		LogKeyNamespace: "dev",
+		LogKeyGroup:     "DEFAULT_GROUP",
+		LogKeyDataId:    "app.json",
+		LogKeyCode:      ErrConfigInvalid.Code,
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("Expected %s = %q, got %v", k, v, entry[k])
+		}
+	}
+}
+
+func TestSDKLoggerForwarding(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &sdkLogger{logger: NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))}
+	logger.Errorf("get config from server error:%v", "timeout")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log entry, got %q", buf.String())
+	}
+	if entry["msg"] != "get config from server error:timeout" || entry[LogKeySource] != "nacos-sdk" {
+		t.Errorf("Unexpected log entry: %v", entry)
+	}
+}
@@ -0,0 +1,387 @@
+// Package loglevel 通过配置中心动态调整日志级别
+//
+// Controller 监听一个 dataId（可指定其中的某个键），按模块名调整已注册日志的级别，
+// 例如排查问题时临时将某个服务调到debug而无需重新部署：
+//
+//	logging:
+//	  level: info          # 全部模块
+//	  modules:
+//	    payment: debug     # 模块 payment 及 payment.xxx、payment/xxx
+//	  ttl: 30m             # 30分钟后自动恢复为注册时的级别
+//
+// 配置被删除、去掉某个模块或TTL到期后，日志恢复为注册时的级别。
+package loglevel
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.yaml.in/yaml/v3"
+)
+
+// Spec 日志级别配置
+type Spec struct {
+	// Level 全部模块的级别
+	Level string `yaml:"level" json:"level"`
+	// Modules 按模块设置的级别，优先于 Level；未配置的子模块继承上级模块（以 . 或 / 分隔）
+	Modules map[string]string `yaml:"modules" json:"modules"`
+	// TTL 生效时长，如 30m，到期后恢复注册时的级别；为空表示一直生效
+	TTL string `yaml:"ttl" json:"ttl"`
+}
+
+// LevelSetter 可动态调整级别的日志
+type LevelSetter interface {
+	// SetLevel 设置级别，level 为 debug/info/warn/error 等
+	SetLevel(level string) error
+	// Level 返回当前级别
+	Level() string
+}
+
+// ZapLevel 将 zap.AtomicLevel 适配为 LevelSetter
+func ZapLevel(level zap.AtomicLevel) LevelSetter {
+	return zapLevel{level: level}
+}
+
+type zapLevel struct {
+	level zap.AtomicLevel
+}
+
+func (l zapLevel) SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.SetLevel(parsed)
+	return nil
+}
+
+func (l zapLevel) Level() string {
+	return l.level.Level().String()
+}
+
+// SlogLevel 将 slog.LevelVar 适配为 LevelSetter
+func SlogLevel(level *slog.LevelVar) LevelSetter {
+	return slogLevel{level: level}
+}
+
+type slogLevel struct {
+	level *slog.LevelVar
+}
+
+func (l slogLevel) SetLevel(level string) error {
+	return l.level.UnmarshalText([]byte(level))
+}
+
+func (l slogLevel) Level() string {
+	return strings.ToLower(l.level.Level().String())
+}
+
+// registration 已注册的日志
+type registration struct {
+	setter LevelSetter
+	// base 注册时的级别，配置移除或到期后恢复
+	base string
+}
+
+// Controller 日志级别控制器
+type Controller struct {
+	source nacos.ConfigSource
+	dataId string
+	group  string
+	key    string
+	logger nacos.Logger
+
+	mu      sync.Mutex
+	modules map[string][]*registration
+	spec    Spec
+	timer   *time.Timer
+	// generation 每次应用配置时递增，避免过期的定时器恢复新配置
+	generation int
+	cancel     func()
+}
+
+// Option 控制器可选配置
+type Option func(*Controller)
+
+// WithKey 指定配置在 dataId 内容中的路径（以 . 分隔），默认使用整个内容
+func WithKey(key string) Option {
+	return func(c *Controller) {
+		c.key = key
+	}
+}
+
+// WithLogger 设置日志，未设置时使用 nacos.DefaultLogger()
+func WithLogger(logger nacos.Logger) Option {
+	return func(c *Controller) {
+		c.logger = logger
+	}
+}
+
+// New 加载日志级别配置并监听变化
+func New(ctx context.Context, source nacos.ConfigSource, dataId, group string, opts ...Option) (*Controller, error) {
+	c := &Controller{
+		source:  source,
+		dataId:  dataId,
+		group:   group,
+		modules: make(map[string][]*registration),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	cancel, err := source.Watch(ctx, dataId, group, c.reload)
+	if err != nil {
+		return nil, err
+	}
+	c.cancel = cancel
+
+	content, err := source.Get(ctx, dataId, group)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := c.applyInitial(content); err != nil {
+		cancel()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Controller) log() nacos.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return nacos.DefaultLogger()
+}
+
+// Register 注册模块的日志，当前配置立即生效
+func (c *Controller) Register(module string, setter LevelSetter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reg := &registration{setter: setter, base: setter.Level()}
+	c.modules[module] = append(c.modules[module], reg)
+	c.applyTo(module, reg)
+}
+
+// RegisterZap 注册zap日志级别
+func (c *Controller) RegisterZap(module string, level zap.AtomicLevel) {
+	c.Register(module, ZapLevel(level))
+}
+
+// RegisterSlog 注册slog日志级别
+func (c *Controller) RegisterSlog(module string, level *slog.LevelVar) {
+	c.Register(module, SlogLevel(level))
+}
+
+// Apply 解析配置并调整全部已注册日志的级别，content 为空时全部恢复
+func (c *Controller) Apply(content string) error {
+	spec, ttl, err := c.parse(content)
+	if err != nil {
+		return nacos.NewNacosError(nacos.ErrConfigInvalid.Code, fmt.Sprintf("日志级别配置无效 [DataId: %s, Group: %s]", c.dataId, c.group), err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.applyLocked(spec, ttl)
+	return nil
+}
+
+// applyInitial 应用首次读取的配置
+// 先监听再读取以免丢失两者之间的推送，读取期间已应用推送时读取结果已过期，直接丢弃
+func (c *Controller) applyInitial(content string) error {
+	spec, ttl, err := c.parse(content)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation > 0 {
+		return nil
+	}
+	if err != nil {
+		return nacos.NewNacosError(nacos.ErrConfigInvalid.Code, fmt.Sprintf("日志级别配置无效 [DataId: %s, Group: %s]", c.dataId, c.group), err)
+	}
+	c.applyLocked(spec, ttl)
+	return nil
+}
+
+// applyLocked 替换当前配置并调整全部日志，调用时需持有锁
+func (c *Controller) applyLocked(spec Spec, ttl time.Duration) {
+	c.generation++
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.spec = spec
+	c.applyAll()
+
+	if ttl > 0 {
+		generation := c.generation
+		c.timer = time.AfterFunc(ttl, func() { c.expire(generation) })
+	}
+}
+
+// Levels 返回已注册模块的当前级别
+func (c *Controller) Levels() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	levels := make(map[string]string, len(c.modules))
+	for module, regs := range c.modules {
+		if len(regs) > 0 {
+			levels[module] = regs[0].setter.Level()
+		}
+	}
+	return levels
+}
+
+// Close 停止监听和定时器，已调整的级别保持不变
+func (c *Controller) Close() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
+
+// reload 处理推送
+func (c *Controller) reload(content string) {
+	if err := c.Apply(content); err != nil {
+		c.log().Warn("日志级别配置无效，保持当前级别",
+			nacos.LogKeyGroup, c.group, nacos.LogKeyDataId, c.dataId, nacos.LogKeyError, err.Error())
+	}
+}
+
+// expire TTL到期后恢复注册时的级别
+func (c *Controller) expire(generation int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.spec = Spec{}
+	c.timer = nil
+	c.applyAll()
+	c.log().Info("日志级别已到期恢复", nacos.LogKeyGroup, c.group, nacos.LogKeyDataId, c.dataId)
+}
+
+// applyAll 按当前配置调整全部日志，调用时需持有锁
+func (c *Controller) applyAll() {
+	modules := make([]string, 0, len(c.modules))
+	for module := range c.modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		for _, reg := range c.modules[module] {
+			c.applyTo(module, reg)
+		}
+	}
+}
+
+// applyTo 调整单个日志的级别，调用时需持有锁
+func (c *Controller) applyTo(module string, reg *registration) {
+	level := c.spec.levelFor(module)
+	if level == "" {
+		level = reg.base
+	}
+	if err := reg.setter.SetLevel(level); err != nil {
+		c.log().Warn("设置日志级别失败", "module", module, "level", level, nacos.LogKeyError, err.Error())
+	}
+}
+
+// levelFor 返回模块的级别：模块自身 > 上级模块 > 全局级别
+func (s Spec) levelFor(module string) string {
+	for name := module; name != ""; name = parentModule(name) {
+		if level, ok := s.Modules[name]; ok && level != "" {
+			return level
+		}
+	}
+	return s.Level
+}
+
+// parentModule 去掉最后一段模块名，payment.api 与 payment/api 的上级都是 payment
+func parentModule(module string) string {
+	i := strings.LastIndexAny(module, "./")
+	if i < 0 {
+		return ""
+	}
+	return module[:i]
+}
+
+// parse 解析配置内容，返回配置与TTL
+func (c *Controller) parse(content string) (Spec, time.Duration, error) {
+	var spec Spec
+	if strings.TrimSpace(content) == "" {
+		return spec, 0, nil
+	}
+
+	var root interface{}
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return spec, 0, err
+	}
+	node := root
+	if c.key != "" {
+		for _, segment := range strings.Split(c.key, ".") {
+			m, ok := node.(map[string]interface{})
+			if !ok {
+				return spec, 0, nil
+			}
+			node = m[segment]
+		}
+	}
+	if node == nil {
+		return spec, 0, nil
+	}
+
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return spec, 0, err
+	}
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return spec, 0, err
+	}
+
+	for _, level := range append([]string{spec.Level}, mapValues(spec.Modules)...) {
+		if level != "" && !validLevel(level) {
+			return spec, 0, fmt.Errorf("无效的日志级别: %s", level)
+		}
+	}
+
+	var ttl time.Duration
+	if spec.TTL != "" {
+		ttl, err = time.ParseDuration(spec.TTL)
+		if err != nil || ttl < 0 {
+			return spec, 0, fmt.Errorf("无效的ttl: %s", spec.TTL)
+		}
+	}
+	return spec, ttl, nil
+}
+
+func validLevel(level string) bool {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "error":
+		return true
+	}
+	return false
+}
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
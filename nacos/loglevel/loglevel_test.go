@@ -0,0 +1,133 @@
+package loglevel
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestController(t *testing.T) {
+	source := nacos.NewMemorySource()
+	ctx := context.Background()
+	source.Publish(ctx, "app.yaml", "", "server:\n  port: 8080\nlogging:\n  level: warn\n")
+
+	controller, err := New(ctx, source, "app.yaml", "", WithKey("logging"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer controller.Close()
+
+	payment := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	paymentAPI := new(slog.LevelVar)
+	order := new(slog.LevelVar)
+	controller.RegisterZap("payment", payment)
+	controller.RegisterSlog("payment/api", paymentAPI)
+	controller.RegisterSlog("order", order)
+
+	// 注册时立即应用当前配置
+	if payment.Level() != zapcore.WarnLevel || order.Level() != slog.LevelWarn {
+		t.Fatalf("Expected global warn level, got %v/%v", payment.Level(), order.Level())
+	}
+
+	source.Publish(ctx, "app.yaml", "", "logging:\n  modules:\n    payment: debug\n")
+	if payment.Level() != zapcore.DebugLevel || paymentAPI.Level() != slog.LevelDebug {
+		t.Errorf("Expected payment modules at debug, got %v/%v", payment.Level(), paymentAPI.Level())
+	}
+	if order.Level() != slog.LevelInfo {
+		t.Errorf("Expected order to revert to base level, got %v", order.Level())
+	}
+
+	// 无效配置保持当前级别
+	source.Publish(ctx, "app.yaml", "", "logging:\n  level: verbose\n")
+	if payment.Level() != zapcore.DebugLevel {
+		t.Errorf("Expected invalid config to be ignored, got %v", payment.Level())
+	}
+
+	source.Publish(ctx, "app.yaml", "", "")
+	if payment.Level() != zapcore.InfoLevel || paymentAPI.Level() != slog.LevelInfo {
+		t.Errorf("Expected all levels restored after delete, got %v/%v", payment.Level(), paymentAPI.Level())
+	}
+}
+
+// racingSource 在返回读取结果前推送更新的内容，模拟读取期间到达的推送
+type racingSource struct {
+	*nacos.MemorySource
+	newer string
+}
+
+func (s *racingSource) Get(ctx context.Context, dataId, group string) (string, error) {
+	content, err := s.MemorySource.Get(ctx, dataId, group)
+	if err == nil {
+		err = s.Publish(ctx, dataId, group, s.newer)
+	}
+	return content, err
+}
+
+func TestPushDuringInitialLoad(t *testing.T) {
+	source := &racingSource{MemorySource: nacos.NewMemorySource(), newer: "level: debug\n"}
+	ctx := context.Background()
+	source.Publish(ctx, "log.yaml", "", "level: warn\n")
+
+	controller, err := New(ctx, source, "log.yaml", "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer controller.Close()
+
+	level := new(slog.LevelVar)
+	controller.RegisterSlog("order", level)
+	if level.Level() != slog.LevelDebug {
+		t.Errorf("Expected the push during the initial load not to be overwritten, got %v", level.Level())
+	}
+}
+
+func TestControllerTTL(t *testing.T) {
+	source := nacos.NewMemorySource()
+	ctx := context.Background()
+
+	controller, err := New(ctx, source, "logging.yaml", "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer controller.Close()
+
+	level := new(slog.LevelVar)
+	controller.RegisterSlog("gateway", level)
+
+	source.Publish(ctx, "logging.yaml", "", "level: debug\nttl: 50ms\n")
+	if level.Level() != slog.LevelDebug {
+		t.Fatalf("Expected debug level, got %v", level.Level())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for level.Level() != slog.LevelInfo {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected level to revert after ttl, got %v", level.Level())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if levels := controller.Levels(); levels["gateway"] != "info" {
+		t.Errorf("Expected Levels() to report info, got %v", levels)
+	}
+}
+
+func TestParentModule(t *testing.T) {
+	spec := Spec{Level: "error", Modules: map[string]string{"payment": "debug", "payment.api": "warn"}}
+	tests := map[string]string{
+		"payment":           "debug",
+		"payment.api":       "warn",
+		"payment.api.v2":    "warn",
+		"payment/callbacks": "debug",
+		"order":             "error",
+	}
+	for module, want := range tests {
+		if got := spec.levelFor(module); got != want {
+			t.Errorf("levelFor(%s) = %s, want %s", module, got, want)
+		}
+	}
+}
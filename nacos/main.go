@@ -3,21 +3,20 @@ package nacos
 import (
 	"context"
 	"fmt"
-	"log"
 )
 
 // NewNacos 创建Nacos客户端并获取配置（向后兼容的简单接口）
 func NewNacos(configPath string) string {
 	client, err := InitNacos(configPath)
 	if err != nil {
-		log.Printf("初始化Nacos客户端失败: %v", err)
+		packageLogger().Error("初始化Nacos客户端失败", errorFields(err)...)
 		return ""
 	}
 
 	ctx := context.Background()
 	data, err := client.GetConfig(ctx, conf.Nacos.Dataid, conf.Nacos.Group)
 	if err != nil {
-		log.Printf("获取配置失败: %v", err)
+		client.clientLogger().Error("获取配置失败", client.logFields(conf.Nacos.Dataid, conf.Nacos.Group, err)...)
 		return ""
 	}
 
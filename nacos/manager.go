@@ -0,0 +1,219 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultClientName Manager 中由 nacos 段创建的客户端名称
+const DefaultClientName = "default"
+
+// Manager 按名称管理多个命名空间（或集群）的客户端
+//
+// 配置文件的 nacos 段创建名为 default 的客户端，namespaces 段中的每一项创建一个同名客户端，
+// 未填写的字段沿用 nacos 段的值，因此只需写出不同的部分：
+//
+//	nacos:
+//	  addr: "10.0.0.1"
+//	  port: 8848
+//	  namespace: "prod"
+//	  dataid: "app.yaml"
+//	namespaces:
+//	  common:
+//	    namespace: "common"
+//	  backup:            # 另一个集群
+//	    addr: "10.0.1.1"
+//	    namespace: "prod"
+type Manager struct {
+	mu      sync.RWMutex
+	clients map[string]*NacosClient
+
+	// audit 全部客户端共用的审计日志，Close 时关闭
+	audit *AuditLog
+}
+
+// NewManager 根据配置创建全部客户端，opts 对每个客户端生效
+// 任一客户端创建失败时关闭已创建的客户端并返回错误
+func NewManager(config Config, opts ...Option) (*Manager, error) {
+	m := &Manager{clients: make(map[string]*NacosClient)}
+
+	// 多个客户端写同一个轮转文件会互相冲突，由 Manager 创建一个共用的审计日志
+	if config.Audit.File != "" {
+		audit, err := NewAuditLog(config.Audit)
+		if err != nil {
+			return nil, err
+		}
+		m.audit = audit
+		opts = append([]Option{WithAuditLog(audit)}, opts...)
+	}
+
+	configs := NamespaceConfigs(config)
+	for _, name := range sortedNames(configs) {
+		client, err := NewNacosClient(configs[name], opts...)
+		if err != nil {
+			m.Close()
+			return nil, NewNacosError(ErrClientInitFailed.Code, fmt.Sprintf("创建客户端失败 [%s]", name), err)
+		}
+		m.clients[name] = client
+	}
+	return m, nil
+}
+
+// NewManagerFromFile 加载配置文件并创建 Manager
+func NewManagerFromFile(configPath string, opts ...Option) (*Manager, error) {
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("加载配置文件失败: %w", err)
+	}
+	return NewManager(config, opts...)
+}
+
+// NamespaceConfigs 返回每个客户端的完整配置，namespaces 中未填写的字段沿用 nacos 段
+// 名称为配置文件中的键（viper 会将其转为小写）
+func NamespaceConfigs(config Config) map[string]Config {
+	base := config
+	base.Namespaces = nil
+
+	configs := map[string]Config{DefaultClientName: base}
+	for name, overlay := range config.Namespaces {
+		c := base
+		c.Nacos = mergeNacosConfig(base.Nacos, overlay)
+		configs[name] = c
+	}
+	return configs
+}
+
+// mergeNacosConfig 用 overlay 中非零值的字段覆盖 base
+// NotLoadCache 为布尔值，无法区分未填写与false，总是沿用 base
+func mergeNacosConfig(base, overlay NacosConfig) NacosConfig {
+	merged := base
+	setString := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	setString(&merged.Namespace, overlay.Namespace)
+	setString(&merged.Addr, overlay.Addr)
+	setString(&merged.Dataid, overlay.Dataid)
+	setString(&merged.Group, overlay.Group)
+	setString(&merged.LogLevel, overlay.LogLevel)
+	setString(&merged.LogDir, overlay.LogDir)
+	setString(&merged.CacheDir, overlay.CacheDir)
+	setString(&merged.Scheme, overlay.Scheme)
+	setString(&merged.ContextPath, overlay.ContextPath)
+	setString(&merged.Username, overlay.Username)
+	setString(&merged.Password, overlay.Password)
+	setString(&merged.Backend, overlay.Backend)
+	if overlay.Port != 0 {
+		merged.Port = overlay.Port
+	}
+	if overlay.TimeoutMs != 0 {
+		merged.TimeoutMs = overlay.TimeoutMs
+	}
+	return merged
+}
+
+// Add 添加客户端，同名客户端已存在时返回错误
+func (m *Manager) Add(name string, client *NacosClient) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.clients == nil {
+		m.clients = make(map[string]*NacosClient)
+	}
+	if _, ok := m.clients[name]; ok {
+		return fmt.Errorf("客户端已存在: %s", name)
+	}
+	m.clients[name] = client
+	return nil
+}
+
+// Client 返回指定名称的客户端，name 为空时返回 default
+func (m *Manager) Client(name string) (*NacosClient, error) {
+	if name == "" {
+		name = DefaultClientName
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	client, ok := m.clients[name]
+	if !ok {
+		return nil, NewNacosError(ErrClientNotFound.Code, fmt.Sprintf("客户端不存在 [%s]", name), nil)
+	}
+	return client, nil
+}
+
+// Names 返回全部客户端名称
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return sortedNames(m.clients)
+}
+
+// GetConfig 通过指定名称的客户端获取配置
+func (m *Manager) GetConfig(ctx context.Context, name, dataId, group string) (string, error) {
+	client, err := m.Client(name)
+	if err != nil {
+		return "", err
+	}
+	return client.GetConfig(ctx, dataId, group)
+}
+
+// PublishConfig 通过指定名称的客户端发布配置
+func (m *Manager) PublishConfig(ctx context.Context, name, dataId, group, content string) error {
+	client, err := m.Client(name)
+	if err != nil {
+		return err
+	}
+	return client.PublishConfig(ctx, dataId, group, content)
+}
+
+// DeleteConfig 通过指定名称的客户端删除配置
+func (m *Manager) DeleteConfig(ctx context.Context, name, dataId, group string) error {
+	client, err := m.Client(name)
+	if err != nil {
+		return err
+	}
+	return client.DeleteConfig(ctx, dataId, group)
+}
+
+// ListenConfig 通过指定名称的客户端监听配置
+func (m *Manager) ListenConfig(ctx context.Context, name, dataId, group string, callback func(string)) error {
+	client, err := m.Client(name)
+	if err != nil {
+		return err
+	}
+	return client.ListenConfig(ctx, dataId, group, callback)
+}
+
+// Close 关闭全部客户端，返回合并后的错误
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	clients := m.clients
+	m.clients = make(map[string]*NacosClient)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, name := range sortedNames(clients) {
+		if err := clients[name].Close(); err != nil {
+			errs = append(errs, fmt.Errorf("关闭客户端失败 [%s]: %w", name, err))
+		}
+	}
+	if m.audit != nil {
+		if err := m.audit.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("关闭审计日志失败: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
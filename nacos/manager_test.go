@@ -0,0 +1,93 @@
+package nacos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+func TestNamespaceConfigs(t *testing.T) {
+	config := *DefaultConfig()
+	config.Nacos.Addr = "10.0.0.1"
+	config.Nacos.Port = 8848
+	config.Nacos.Namespace = "prod"
+	config.Nacos.Dataid = "app.yaml"
+	config.Namespaces = map[string]NacosConfig{
+		"common": {Namespace: "common"},
+		"backup": {Addr: "10.0.1.1", Port: 9848},
+	}
+
+	configs := NamespaceConfigs(config)
+	if len(configs) != 3 {
+		t.Fatalf("Expected default, common and backup, got %d configs", len(configs))
+	}
+
+	common := configs["common"].Nacos
+	if common.Namespace != "common" || common.Addr != "10.0.0.1" || common.Dataid != "app.yaml" || common.Group != "DEFAULT_GROUP" {
+		t.Errorf("Expected common to inherit unset fields, got %+v", common)
+	}
+	backup := configs["backup"].Nacos
+	if backup.Addr != "10.0.1.1" || backup.Port != 9848 || backup.Namespace != "prod" {
+		t.Errorf("Expected backup to override cluster address, got %+v", backup)
+	}
+	if configs[DefaultClientName].Nacos != config.Nacos || configs["common"].Namespaces != nil {
+		t.Errorf("Unexpected default config: %+v", configs[DefaultClientName])
+	}
+}
+
+func TestManager(t *testing.T) {
+	config := *DefaultConfig()
+	config.Nacos.Addr = "127.0.0.1"
+	config.Nacos.Port = 8848
+	config.Nacos.Dataid = "app.yaml"
+	config.Namespaces = map[string]NacosConfig{"common": {Namespace: "common"}}
+
+	manager, err := NewManager(config, WithConfigClient(nacostest.NewConfigClient()))
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if names := manager.Names(); len(names) != 2 || names[0] != "common" || names[1] != DefaultClientName {
+		t.Errorf("Unexpected client names: %v", names)
+	}
+	if client, _ := manager.Client("common"); client.config.Nacos.Namespace != "common" {
+		t.Errorf("Expected common client to use the common namespace, got %q", client.config.Nacos.Namespace)
+	}
+
+	// 按名称路由到各自的命名空间
+	shared := nacostest.NewConfigClient()
+	shared.Set("db.yaml", "", "host: shared")
+	if err := manager.Add("shared", newTestClient(shared)); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := manager.Add("shared", newTestClient(shared)); err == nil {
+		t.Error("Expected duplicate name to fail")
+	}
+
+	ctx := context.Background()
+	if content, err := manager.GetConfig(ctx, "shared", "db.yaml", ""); err != nil || content != "host: shared" {
+		t.Errorf("GetConfig(shared) = %q, %v", content, err)
+	}
+	if err := manager.PublishConfig(ctx, "shared", "redis.yaml", "", "addr: 1"); err != nil {
+		t.Errorf("PublishConfig(shared) error = %v", err)
+	}
+	if content, ok := shared.Get("redis.yaml", ""); !ok || content != "addr: 1" {
+		t.Errorf("Expected publish to reach the shared namespace, got %q", content)
+	}
+
+	if _, err := manager.GetConfig(ctx, "missing", "db.yaml", ""); ErrorCode(err) != ErrClientNotFound.Code {
+		t.Errorf("Expected %s, got %v", ErrClientNotFound.Code, err)
+	}
+
+	if err := manager.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if names := manager.Names(); len(names) != 0 {
+		t.Errorf("Expected no clients after Close, got %v", names)
+	}
+	// 注入的配置客户端由调用方关闭
+	if _, err := shared.GetConfig(vo.ConfigParam{DataId: "db.yaml", Group: DefaultGroup}); err != nil {
+		t.Errorf("Expected injected client to stay open after Close, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package nacos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 指标中的操作名称
+const (
+	opGet     = "get"
+	opPublish = "publish"
+	opDelete  = "delete"
+	opListen  = "listen"
+)
+
+// clientMetrics 客户端的Prometheus指标
+type clientMetrics struct {
+	requests   *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	errors     *prometheus.CounterVec
+	pushes     *prometheus.CounterVec
+	lastUpdate *prometheus.GaugeVec
+	panics     *prometheus.CounterVec
+}
+
+// WithMetrics 开启Prometheus指标并注册到指定的registry
+//
+// 暴露的指标：
+//   - nacos_client_requests_total{operation,group,data_id,result}：请求次数
+//   - nacos_client_request_duration_seconds{operation,group,data_id,result}：请求耗时
+//   - nacos_client_errors_total{operation,code}：按 NacosError.Code 统计的错误次数
+//   - nacos_client_listener_pushes_total{group,data_id}：监听推送次数
+//   - nacos_client_last_update_timestamp_seconds{group,data_id}：最近一次成功获取或收到推送的时间
+//   - nacos_client_callback_panics_total{group,data_id}：监听回调发生panic的次数
+//
+// 多个客户端注册到同一registry时共用同一组指标
+func WithMetrics(registry prometheus.Registerer) Option {
+	return func(c *NacosClient) {
+		c.metrics = newClientMetrics(registry)
+	}
+}
+
+func newClientMetrics(registry prometheus.Registerer) *clientMetrics {
+	m := &clientMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nacos",
+			Subsystem: "client",
+			Name:      "requests_total",
+			Help:      "Total number of Nacos config requests.",
+		}, []string{"operation", "group", "data_id", "result"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "nacos",
+			Subsystem: "client",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Nacos config requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "group", "data_id", "result"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nacos",
+			Subsystem: "client",
+			Name:      "errors_total",
+			Help:      "Total number of Nacos config errors by error code.",
+		}, []string{"operation", "code"}),
+		pushes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nacos",
+			Subsystem: "client",
+			Name:      "listener_pushes_total",
+			Help:      "Total number of config change pushes received by listeners.",
+		}, []string{"group", "data_id"}),
+		lastUpdate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "nacos",
+			Subsystem: "client",
+			Name:      "last_update_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful fetch or push of a config.",
+		}, []string{"group", "data_id"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nacos",
+			Subsystem: "client",
+			Name:      "callback_panics_total",
+			Help:      "Total number of panics recovered from listener callbacks.",
+		}, []string{"group", "data_id"}),
+	}
+
+	if registry != nil {
+		m.requests = registerCollector(registry, m.requests)
+		m.latency = registerCollector(registry, m.latency)
+		m.errors = registerCollector(registry, m.errors)
+		m.pushes = registerCollector(registry, m.pushes)
+		m.lastUpdate = registerCollector(registry, m.lastUpdate)
+		m.panics = registerCollector(registry, m.panics)
+	}
+	return m
+}
+
+// registerCollector 注册指标，已注册过时复用已有的指标
+func registerCollector[T prometheus.Collector](registry prometheus.Registerer, collector T) T {
+	if err := registry.Register(collector); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+	}
+	return collector
+}
+
+// observe 记录一次请求
+func (m *clientMetrics) observe(operation, dataId, group string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	result := "success"
+	if err != nil {
+		result = "error"
+		code := ErrorCode(err)
+		if code == "" {
+			code = "UNKNOWN"
+		}
+		m.errors.WithLabelValues(operation, code).Inc()
+	}
+
+	m.requests.WithLabelValues(operation, group, dataId, result).Inc()
+	m.latency.WithLabelValues(operation, group, dataId, result).Observe(time.Since(start).Seconds())
+
+	if err == nil && operation == opGet {
+		m.lastUpdate.WithLabelValues(group, dataId).SetToCurrentTime()
+	}
+}
+
+// push 记录一次监听推送
+func (m *clientMetrics) push(dataId, group string) {
+	if m == nil {
+		return
+	}
+	m.pushes.WithLabelValues(group, dataId).Inc()
+}
+
+// applied 记录一次推送被全部回调接受，解密、占位符解析或校验失败的推送不更新时间戳
+func (m *clientMetrics) applied(dataId, group string) {
+	if m == nil {
+		return
+	}
+	m.lastUpdate.WithLabelValues(group, dataId).SetToCurrentTime()
+}
+
+// callbackPanic 记录一次监听回调panic
+func (m *clientMetrics) callbackPanic(dataId, group string) {
+	if m == nil {
+		return
+	}
+	m.panics.WithLabelValues(group, dataId).Inc()
+}
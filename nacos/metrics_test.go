@@ -0,0 +1,66 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestClientMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("app.yaml", "DEFAULT_GROUP", "a: 1")
+
+	client := newTestClient(sdk, WithMetrics(registry))
+	ctx := context.Background()
+
+	if _, err := client.GetConfig(ctx, "app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	sdk.FailTimes(nacostest.OpGet, errors.New("server error"), 1)
+	if _, err := client.GetConfig(ctx, "app.yaml", "DEFAULT_GROUP"); err == nil {
+		t.Fatal("Expected GetConfig() to fail")
+	}
+	if err := client.ListenConfig(ctx, "app.yaml", "DEFAULT_GROUP", nil); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: "a: 2"})
+
+	m := client.metrics
+	if v := testutil.ToFloat64(m.requests.WithLabelValues(opGet, "DEFAULT_GROUP", "app.yaml", "success")); v != 1 {
+		t.Errorf("Expected 1 successful get, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.errors.WithLabelValues(opGet, ErrOperationFailed.Code)); v != 1 {
+		t.Errorf("Expected 1 %s error, got %v", ErrOperationFailed.Code, v)
+	}
+	if v := testutil.ToFloat64(m.pushes.WithLabelValues("DEFAULT_GROUP", "app.yaml")); v != 1 {
+		t.Errorf("Expected 1 push, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.lastUpdate.WithLabelValues("DEFAULT_GROUP", "app.yaml")); v == 0 {
+		t.Error("Expected last update timestamp to be set")
+	}
+
+	// 校验拒绝的推送只计数，不更新时间戳
+	m.lastUpdate.WithLabelValues("DEFAULT_GROUP", "app.yaml").Set(0)
+	client.RegisterValidator("app.yaml", "DEFAULT_GROUP", ValidatorFunc(func(dataId, content string) error {
+		return errors.New("rejected")
+	}))
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: "a: 3"})
+	if v := testutil.ToFloat64(m.pushes.WithLabelValues("DEFAULT_GROUP", "app.yaml")); v != 2 {
+		t.Errorf("Expected 2 pushes, got %v", v)
+	}
+	if v := testutil.ToFloat64(m.lastUpdate.WithLabelValues("DEFAULT_GROUP", "app.yaml")); v != 0 {
+		t.Errorf("Expected rejected push to leave last update timestamp unchanged, got %v", v)
+	}
+
+	// 同一registry上创建第二个客户端时复用已注册的指标
+	other := newTestClient(sdk, WithMetrics(registry))
+	if other.metrics.requests != m.requests {
+		t.Error("Expected metrics to be shared on the same registry")
+	}
+}
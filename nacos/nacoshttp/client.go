@@ -0,0 +1,554 @@
+// Package nacoshttp 基于 net/http 的轻量Nacos配置客户端，不依赖 nacos-sdk-go
+//
+// 通过Nacos open API 实现配置的获取、发布、删除、搜索以及长轮询监听，
+// 只依赖标准库，适合对二进制大小敏感的程序直接使用；
+// nacos 包在 NacosConfig.Backend 为 http 时也使用它代替SDK。
+//
+// 获取、发布、删除支持 v1（/v1/cs/configs，Nacos 1.x 与 2.x 均可用）和 v2（/v2/cs/config，Nacos 2.2+）接口，
+// 监听与搜索只有 v1 接口。
+package nacoshttp
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 支持的 open API 版本
+const (
+	APIv1 = "v1"
+	APIv2 = "v2"
+)
+
+// 默认值
+const (
+	DefaultGroup           = "DEFAULT_GROUP"
+	DefaultContextPath     = "/nacos"
+	defaultTimeout         = 5 * time.Second
+	defaultLongPollTimeout = 30 * time.Second
+	defaultRetryInterval   = time.Second
+)
+
+// ErrClosed 客户端已关闭
+var ErrClosed = errors.New("nacoshttp: client closed")
+
+// Config 客户端配置
+type Config struct {
+	// ServerAddr 服务端地址，如 127.0.0.1:8848 或 http://nacos.example.com:8848，
+	// 多个地址以逗号分隔，请求失败时依次尝试
+	ServerAddr string
+	// ContextPath 默认 /nacos
+	ContextPath string
+	// Scheme ServerAddr 未带协议时使用，默认 http
+	Scheme string
+	// Namespace 命名空间ID，为空表示 public
+	Namespace string
+	// Username/Password 开启鉴权时使用，登录获得的token会在过期前自动刷新
+	Username string
+	Password string
+	// APIVersion 获取、发布、删除使用的接口版本，v1（默认）或 v2
+	APIVersion string
+	// Timeout 单次请求超时，默认5秒
+	Timeout time.Duration
+	// LongPollTimeout 长轮询超时，默认30秒
+	LongPollTimeout time.Duration
+	// HTTPClient 自定义HTTP客户端，为nil时使用默认客户端
+	HTTPClient *http.Client
+	// OnError 长轮询出错时调用，为nil时忽略（出错后会间隔1秒重试）
+	OnError func(err error)
+}
+
+// Error 服务端返回的错误
+type Error struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("nacoshttp: status %d: %s", e.StatusCode, e.Body)
+}
+
+// Client Nacos HTTP 客户端
+type Client struct {
+	config  Config
+	servers []string
+	http    *http.Client
+
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpiry time.Time
+
+	// 长轮询监听
+	listenMu  sync.Mutex
+	listeners map[string]*listener
+	pollWake  chan struct{}
+	pollStop  func()
+	polling   bool
+
+	closeOnce sync.Once
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// New 创建客户端
+func New(config Config) (*Client, error) {
+	if config.ServerAddr == "" {
+		return nil, errors.New("nacoshttp: server address is required")
+	}
+	if config.ContextPath == "" {
+		config.ContextPath = DefaultContextPath
+	}
+	if config.Scheme == "" {
+		config.Scheme = "http"
+	}
+	switch config.APIVersion {
+	case "":
+		config.APIVersion = APIv1
+	case APIv1, APIv2:
+	default:
+		return nil, fmt.Errorf("nacoshttp: unsupported api version %q", config.APIVersion)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.LongPollTimeout <= 0 {
+		config.LongPollTimeout = defaultLongPollTimeout
+	}
+
+	c := &Client{
+		config:    config,
+		http:      config.HTTPClient,
+		listeners: make(map[string]*listener),
+		pollWake:  make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	if c.http == nil {
+		c.http = &http.Client{}
+	}
+	for _, addr := range strings.Split(config.ServerAddr, ",") {
+		addr = strings.TrimSuffix(strings.TrimSpace(addr), "/")
+		if addr == "" {
+			continue
+		}
+		if !strings.Contains(addr, "://") {
+			addr = config.Scheme + "://" + addr
+		}
+		c.servers = append(c.servers, addr+"/"+strings.Trim(config.ContextPath, "/"))
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	return c, nil
+}
+
+// GetConfig 获取配置，配置不存在时返回空字符串
+func (c *Client) GetConfig(ctx context.Context, dataId, group string) (string, error) {
+	group = defaultGroup(group)
+	if c.config.APIVersion == APIv2 {
+		query := url.Values{"dataId": {dataId}, "group": {group}, "namespaceId": {c.config.Namespace}}
+		var content string
+		found, err := c.doV2(ctx, http.MethodGet, "/v2/cs/config", query, &content)
+		if err != nil || !found {
+			return "", err
+		}
+		return content, nil
+	}
+
+	query := url.Values{"dataId": {dataId}, "group": {group}, "tenant": {c.config.Namespace}}
+	body, status, err := c.do(ctx, http.MethodGet, "/v1/cs/configs", query, nil, c.config.Timeout)
+	if err != nil {
+		return "", err
+	}
+	switch status {
+	case http.StatusOK:
+		return string(body), nil
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", &Error{StatusCode: status, Body: string(body)}
+	}
+}
+
+// PublishParam 发布配置的参数
+type PublishParam struct {
+	DataId  string
+	Group   string
+	Content string
+	// Type 配置格式，如 yaml、json、properties、text
+	Type    string
+	AppName string
+	Desc    string
+	// Tags 配置标签，多个以逗号分隔
+	Tags    string
+	SrcUser string
+	// CasMd5 不为空时只有服务端当前内容的md5与之相同才发布
+	CasMd5 string
+}
+
+// PublishConfig 发布配置
+func (c *Client) PublishConfig(ctx context.Context, dataId, group, content string) error {
+	return c.Publish(ctx, PublishParam{DataId: dataId, Group: group, Content: content})
+}
+
+// Publish 按参数发布配置
+func (c *Client) Publish(ctx context.Context, param PublishParam) error {
+	if param.DataId == "" || param.Content == "" {
+		return errors.New("nacoshttp: dataId and content are required")
+	}
+	form := url.Values{
+		"dataId":  {param.DataId},
+		"group":   {defaultGroup(param.Group)},
+		"content": {param.Content},
+	}
+	setIf := func(key, value string) {
+		if value != "" {
+			form.Set(key, value)
+		}
+	}
+	setIf("type", param.Type)
+	setIf("appName", param.AppName)
+	setIf("desc", param.Desc)
+	setIf("config_tags", param.Tags)
+	setIf("src_user", param.SrcUser)
+	setIf("casMd5", param.CasMd5)
+
+	if c.config.APIVersion == APIv2 {
+		form.Set("namespaceId", c.config.Namespace)
+		return c.expectTrueV2(ctx, http.MethodPost, "/v2/cs/config", form)
+	}
+	form.Set("tenant", c.config.Namespace)
+	return c.expectTrue(ctx, http.MethodPost, "/v1/cs/configs", form)
+}
+
+// DeleteConfig 删除配置
+func (c *Client) DeleteConfig(ctx context.Context, dataId, group string) error {
+	group = defaultGroup(group)
+	if c.config.APIVersion == APIv2 {
+		query := url.Values{"dataId": {dataId}, "group": {group}, "namespaceId": {c.config.Namespace}}
+		return c.expectTrueV2(ctx, http.MethodDelete, "/v2/cs/config", query)
+	}
+	query := url.Values{"dataId": {dataId}, "group": {group}, "tenant": {c.config.Namespace}}
+	return c.expectTrue(ctx, http.MethodDelete, "/v1/cs/configs", query)
+}
+
+// ConfigInfo 配置内容及元数据，CreateTime、ModifyTime 为毫秒时间戳
+type ConfigInfo struct {
+	ID         json.Number `json:"id"`
+	DataId     string      `json:"dataId"`
+	Group      string      `json:"group"`
+	Tenant     string      `json:"tenant"`
+	Content    string      `json:"content"`
+	MD5        string      `json:"md5"`
+	Type       string      `json:"type"`
+	AppName    string      `json:"appName"`
+	Desc       string      `json:"desc"`
+	Tags       string      `json:"configTags"`
+	CreateUser string      `json:"createUser"`
+	CreateIP   string      `json:"createIp"`
+	CreateTime int64       `json:"createTime"`
+	ModifyTime int64       `json:"modifyTime"`
+}
+
+// GetConfigInfo 获取配置内容及元数据（v1 show=all 接口），配置不存在时返回nil
+func (c *Client) GetConfigInfo(ctx context.Context, dataId, group string) (*ConfigInfo, error) {
+	query := url.Values{"dataId": {dataId}, "group": {defaultGroup(group)}, "tenant": {c.config.Namespace}, "show": {"all"}}
+	body, status, err := c.do(ctx, http.MethodGet, "/v1/cs/configs", query, nil, c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case status == http.StatusNotFound:
+		return nil, nil
+	case status != http.StatusOK:
+		return nil, &Error{StatusCode: status, Body: string(body)}
+	}
+
+	// 部分版本配置不存在时返回200和空内容
+	body = []byte(strings.TrimSpace(string(body)))
+	if len(body) == 0 || string(body) == "null" {
+		return nil, nil
+	}
+	var info ConfigInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("nacoshttp: decode config info: %w", err)
+	}
+	return &info, nil
+}
+
+// SearchParam 搜索配置的参数
+type SearchParam struct {
+	// Search accurate（默认）或 blur，blur 时 dataId、group 可使用 * 通配
+	Search   string
+	DataId   string
+	Group    string
+	AppName  string
+	Tags     string
+	PageNo   int
+	PageSize int
+}
+
+// ConfigItem 搜索结果中的配置
+type ConfigItem struct {
+	ID      json.Number `json:"id"`
+	DataId  string      `json:"dataId"`
+	Group   string      `json:"group"`
+	Content string      `json:"content"`
+	MD5     string      `json:"md5"`
+	Tenant  string      `json:"tenant"`
+	AppName string      `json:"appName"`
+	Type    string      `json:"type"`
+}
+
+// ConfigPage 搜索结果
+type ConfigPage struct {
+	TotalCount     int          `json:"totalCount"`
+	PageNumber     int          `json:"pageNumber"`
+	PagesAvailable int          `json:"pagesAvailable"`
+	PageItems      []ConfigItem `json:"pageItems"`
+}
+
+// SearchConfig 搜索配置
+func (c *Client) SearchConfig(ctx context.Context, param SearchParam) (*ConfigPage, error) {
+	if param.Search == "" {
+		param.Search = "accurate"
+	}
+	if param.PageNo <= 0 {
+		param.PageNo = 1
+	}
+	if param.PageSize <= 0 {
+		param.PageSize = 10
+	}
+	query := url.Values{
+		"search":   {param.Search},
+		"dataId":   {param.DataId},
+		"group":    {param.Group},
+		"appName":  {param.AppName},
+		"tenant":   {c.config.Namespace},
+		"pageNo":   {strconv.Itoa(param.PageNo)},
+		"pageSize": {strconv.Itoa(param.PageSize)},
+	}
+	if param.Tags != "" {
+		query.Set("config_tags", param.Tags)
+	}
+
+	body, status, err := c.do(ctx, http.MethodGet, "/v1/cs/configs", query, nil, c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, &Error{StatusCode: status, Body: string(body)}
+	}
+	var page ConfigPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("nacoshttp: decode search result: %w", err)
+	}
+	return &page, nil
+}
+
+// Close 停止监听并关闭客户端
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		c.listenMu.Lock()
+		polling := c.polling
+		c.listenMu.Unlock()
+		if polling {
+			<-c.done
+		}
+	})
+}
+
+// expectTrue 执行v1写操作，服务端返回 true 表示成功
+func (c *Client) expectTrue(ctx context.Context, method, path string, params url.Values) error {
+	body, status, err := c.do(ctx, method, path, params, nil, c.config.Timeout)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK || strings.TrimSpace(string(body)) != "true" {
+		return &Error{StatusCode: status, Body: string(body)}
+	}
+	return nil
+}
+
+// expectTrueV2 执行v2写操作，data 为 true 表示成功
+func (c *Client) expectTrueV2(ctx context.Context, method, path string, params url.Values) error {
+	var ok bool
+	if _, err := c.doV2(ctx, method, path, params, &ok); err != nil {
+		return err
+	}
+	if !ok {
+		return &Error{StatusCode: http.StatusOK, Body: "false"}
+	}
+	return nil
+}
+
+// v2Response v2 接口的统一响应
+type v2Response struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// v2 接口中表示配置不存在的错误码
+const v2CodeConfigNotFound = 20004
+
+// doV2 执行v2请求并解析 data，配置不存在时返回 found=false
+func (c *Client) doV2(ctx context.Context, method, path string, params url.Values, data interface{}) (found bool, err error) {
+	body, status, err := c.do(ctx, method, path, params, nil, c.config.Timeout)
+	if err != nil {
+		return false, err
+	}
+
+	var resp v2Response
+	if jerr := json.Unmarshal(body, &resp); jerr != nil {
+		return false, &Error{StatusCode: status, Body: string(body)}
+	}
+	if resp.Code == v2CodeConfigNotFound {
+		return false, nil
+	}
+	if status != http.StatusOK || resp.Code != 0 {
+		return false, &Error{StatusCode: status, Body: string(body)}
+	}
+	if err := json.Unmarshal(resp.Data, data); err != nil {
+		return false, fmt.Errorf("nacoshttp: decode response: %w", err)
+	}
+	return true, nil
+}
+
+// do 发送请求，POST 时 params 作为表单，其他方法作为查询参数；连接失败或5xx时依次尝试其他服务端
+func (c *Client) do(ctx context.Context, method, path string, params url.Values, header http.Header, timeout time.Duration) ([]byte, int, error) {
+	query, form := params, url.Values(nil)
+	if method == http.MethodPost {
+		query, form = nil, params
+	}
+
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if token != "" {
+		query = url.Values{"accessToken": {token}}
+		if method != http.MethodPost {
+			for key, values := range params {
+				query[key] = values
+			}
+		}
+	}
+
+	var lastErr error
+	for _, server := range c.servers {
+		body, status, err := c.send(ctx, method, server+path, query, form, header, timeout)
+		if err == nil && status < http.StatusInternalServerError {
+			return body, status, nil
+		}
+		if err == nil {
+			err = &Error{StatusCode: status, Body: string(body)}
+		}
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		lastErr = err
+	}
+	return nil, 0, lastErr
+}
+
+// send 发送单个请求
+func (c *Client) send(ctx context.Context, method, endpoint string, query, form url.Values, header http.Header, timeout time.Duration) ([]byte, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var reqBody io.Reader
+	if form != nil {
+		reqBody = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return nil, 0, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+// accessToken 返回鉴权token，未配置用户名时返回空字符串
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	if c.config.Username == "" {
+		return "", nil
+	}
+
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	form := url.Values{"username": {c.config.Username}, "password": {c.config.Password}}
+	var lastErr error
+	for _, server := range c.servers {
+		body, status, err := c.send(ctx, http.MethodPost, server+"/v1/auth/login", nil, form, nil, c.config.Timeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if status != http.StatusOK {
+			lastErr = &Error{StatusCode: status, Body: string(body)}
+			continue
+		}
+
+		var resp struct {
+			AccessToken string `json:"accessToken"`
+			TokenTTL    int64  `json:"tokenTtl"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil || resp.AccessToken == "" {
+			return "", fmt.Errorf("nacoshttp: invalid login response: %s", body)
+		}
+		// 提前十分之一的有效期刷新
+		ttl := time.Duration(resp.TokenTTL) * time.Second
+		c.token = resp.AccessToken
+		c.tokenExpiry = time.Now().Add(ttl - ttl/10)
+		return c.token, nil
+	}
+	return "", fmt.Errorf("nacoshttp: login failed: %w", lastErr)
+}
+
+// MD5 返回内容的md5，与服务端计算方式一致，空内容返回空字符串
+func MD5(content string) string {
+	if content == "" {
+		return ""
+	}
+	sum := md5.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func defaultGroup(group string) string {
+	if group == "" {
+		return DefaultGroup
+	}
+	return group
+}
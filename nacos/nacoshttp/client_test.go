@@ -0,0 +1,294 @@
+package nacoshttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer 实现配置相关 open API 的内存服务端
+type fakeServer struct {
+	mu      sync.Mutex
+	configs map[string]string
+	// 发布时携带的表单，用于 show=all 返回元数据
+	forms   map[string]url.Values
+	changed chan struct{}
+	token   string
+}
+
+func newFakeServer(t *testing.T) (*fakeServer, *httptest.Server) {
+	f := &fakeServer{configs: make(map[string]string), forms: make(map[string]url.Values), changed: make(chan struct{})}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeServer) key(values url.Values) string {
+	tenant := values.Get("tenant") + values.Get("namespaceId")
+	return tenant + "|" + values.Get("group") + "|" + values.Get("dataId")
+}
+
+func (f *fakeServer) set(key, content string) {
+	f.mu.Lock()
+	if content == "" {
+		delete(f.configs, key)
+	} else {
+		f.configs[key] = content
+	}
+	close(f.changed)
+	f.changed = make(chan struct{})
+	f.mu.Unlock()
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	if f.token != "" && r.URL.Path != "/nacos/v1/auth/login" && r.URL.Query().Get("accessToken") != f.token {
+		http.Error(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/nacos/v1/auth/login":
+		if r.PostForm.Get("username") != "nacos" || r.PostForm.Get("password") != "secret" {
+			http.Error(w, "bad credentials", http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"accessToken": f.token, "tokenTtl": 18000})
+	case "/nacos/v1/cs/configs":
+		key := f.key(r.Form)
+		switch r.Method {
+		case http.MethodGet:
+			f.mu.Lock()
+			content, ok := f.configs[key]
+			form := f.forms[key]
+			f.mu.Unlock()
+			if !ok {
+				http.Error(w, "config data not exist", http.StatusNotFound)
+				return
+			}
+			if r.Form.Get("show") == "all" {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"dataId": form.Get("dataId"), "group": form.Get("group"), "tenant": form.Get("tenant"),
+					"content": content, "md5": MD5(content), "type": form.Get("type"), "appName": form.Get("appName"),
+					"desc": form.Get("desc"), "configTags": form.Get("config_tags"), "createUser": form.Get("src_user"),
+					"createTime": 1700000000000, "modifyTime": 1700000001000,
+				})
+				return
+			}
+			w.Write([]byte(content))
+		case http.MethodPost:
+			f.mu.Lock()
+			f.forms[key] = r.PostForm
+			f.mu.Unlock()
+			f.set(key, r.PostForm.Get("content"))
+			w.Write([]byte("true"))
+		case http.MethodDelete:
+			f.set(key, "")
+			w.Write([]byte("true"))
+		}
+	case "/nacos/v2/cs/config":
+		key := f.key(r.Form)
+		resp := map[string]interface{}{"code": 0, "message": "success", "data": true}
+		switch r.Method {
+		case http.MethodGet:
+			f.mu.Lock()
+			content, ok := f.configs[key]
+			f.mu.Unlock()
+			if !ok {
+				resp = map[string]interface{}{"code": 20004, "message": "config data not exist"}
+			} else {
+				resp["data"] = content
+			}
+		case http.MethodPost:
+			f.set(key, r.PostForm.Get("content"))
+		case http.MethodDelete:
+			f.set(key, "")
+		}
+		json.NewEncoder(w).Encode(resp)
+	case "/nacos/v1/cs/configs/listener":
+		timeout, _ := time.ParseDuration(r.Header.Get("Long-Pulling-Timeout") + "ms")
+		deadline := time.After(timeout)
+		for {
+			f.mu.Lock()
+			var changed []string
+			for _, line := range strings.Split(r.PostForm.Get("Listening-Configs"), lineSeparator) {
+				parts := strings.Split(line, wordSeparator)
+				if len(parts) < 3 {
+					continue
+				}
+				tenant := ""
+				if len(parts) > 3 {
+					tenant = parts[3]
+				}
+				if MD5(f.configs[tenant+"|"+parts[1]+"|"+parts[0]]) != parts[2] {
+					changed = append(changed, parts[0]+wordSeparator+parts[1]+wordSeparator+tenant+lineSeparator)
+				}
+			}
+			wait := f.changed
+			f.mu.Unlock()
+
+			if len(changed) > 0 {
+				w.Write([]byte(url.QueryEscape(strings.Join(changed, ""))))
+				return
+			}
+			select {
+			case <-wait:
+			case <-deadline:
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestClient(t *testing.T) {
+	for _, version := range []string{APIv1, APIv2} {
+		t.Run(version, func(t *testing.T) {
+			_, srv := newFakeServer(t)
+			client, err := New(Config{ServerAddr: srv.URL, Namespace: "dev", APIVersion: version})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer client.Close()
+			ctx := context.Background()
+
+			if content, err := client.GetConfig(ctx, "app.yaml", ""); err != nil || content != "" {
+				t.Errorf("Expected missing config to return empty content, got %q, %v", content, err)
+			}
+			if err := client.PublishConfig(ctx, "app.yaml", "", "a: 1"); err != nil {
+				t.Fatalf("PublishConfig() error = %v", err)
+			}
+			if content, err := client.GetConfig(ctx, "app.yaml", DefaultGroup); err != nil || content != "a: 1" {
+				t.Errorf("GetConfig() = %q, %v", content, err)
+			}
+			if err := client.DeleteConfig(ctx, "app.yaml", ""); err != nil {
+				t.Fatalf("DeleteConfig() error = %v", err)
+			}
+			if content, _ := client.GetConfig(ctx, "app.yaml", ""); content != "" {
+				t.Errorf("Expected deleted config to be empty, got %q", content)
+			}
+		})
+	}
+}
+
+func TestGetConfigInfo(t *testing.T) {
+	_, srv := newFakeServer(t)
+	client, _ := New(Config{ServerAddr: srv.URL, Namespace: "dev"})
+	ctx := context.Background()
+
+	if info, err := client.GetConfigInfo(ctx, "app.yaml", ""); err != nil || info != nil {
+		t.Errorf("Expected missing config to return nil, got %+v, %v", info, err)
+	}
+	err := client.Publish(ctx, PublishParam{
+		DataId: "app.yaml", Content: "a: 1", Type: "yaml", AppName: "order", Desc: "订单服务", Tags: "core,db", SrcUser: "ci",
+	})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	info, err := client.GetConfigInfo(ctx, "app.yaml", "")
+	if err != nil || info == nil {
+		t.Fatalf("GetConfigInfo() = %+v, %v", info, err)
+	}
+	want := ConfigInfo{
+		DataId: "app.yaml", Group: DefaultGroup, Tenant: "dev", Content: "a: 1", MD5: MD5("a: 1"), Type: "yaml",
+		AppName: "order", Desc: "订单服务", Tags: "core,db", CreateUser: "ci", CreateTime: 1700000000000, ModifyTime: 1700000001000,
+	}
+	if *info != want {
+		t.Errorf("GetConfigInfo() = %+v, want %+v", *info, want)
+	}
+}
+
+func TestListen(t *testing.T) {
+	fake, srv := newFakeServer(t)
+	fake.set("|DEFAULT_GROUP|app.yaml", "a: 1")
+	client, err := New(Config{ServerAddr: srv.URL, LongPollTimeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	pushes := make(chan string, 10)
+	cancel, err := client.Listen(ctx, "app.yaml", "", func(content string) { pushes <- content })
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	other := make(chan string, 10)
+	if _, err := client.Listen(ctx, "other.yaml", "SHARED", func(content string) { other <- content }); err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+
+	expect := func(ch chan string, want string) {
+		t.Helper()
+		select {
+		case got := <-ch:
+			if got != want {
+				t.Errorf("Expected push %q, got %q", want, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for push %q", want)
+		}
+	}
+
+	client.PublishConfig(ctx, "app.yaml", "", "a: 2")
+	expect(pushes, "a: 2")
+	client.PublishConfig(ctx, "other.yaml", "SHARED", "b: 1")
+	expect(other, "b: 1")
+	client.DeleteConfig(ctx, "app.yaml", "")
+	expect(pushes, "")
+
+	cancel()
+	client.PublishConfig(ctx, "app.yaml", "", "a: 3")
+	client.PublishConfig(ctx, "other.yaml", "SHARED", "b: 2")
+	expect(other, "b: 2")
+	select {
+	case got := <-pushes:
+		t.Errorf("Expected no push after cancel, got %q", got)
+	default:
+	}
+
+	client.Close()
+	if _, err := client.Listen(ctx, "app.yaml", "", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed after Close, got %v", err)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	fake, srv := newFakeServer(t)
+	fake.token = "token-1"
+	fake.set("|DEFAULT_GROUP|app.yaml", "a: 1")
+
+	client, _ := New(Config{ServerAddr: srv.URL, Username: "nacos", Password: "secret"})
+	if content, err := client.GetConfig(context.Background(), "app.yaml", ""); err != nil || content != "a: 1" {
+		t.Errorf("GetConfig() with login = %q, %v", content, err)
+	}
+
+	client, _ = New(Config{ServerAddr: srv.URL, Username: "nacos", Password: "wrong"})
+	if _, err := client.GetConfig(context.Background(), "app.yaml", ""); err == nil {
+		t.Error("Expected login with wrong password to fail")
+	}
+}
+
+func TestFailover(t *testing.T) {
+	fake, srv := newFakeServer(t)
+	fake.set("|DEFAULT_GROUP|app.yaml", "a: 1")
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	client, _ := New(Config{ServerAddr: down.URL + "," + srv.URL})
+	if content, err := client.GetConfig(context.Background(), "app.yaml", ""); err != nil || content != "a: 1" {
+		t.Errorf("Expected failover to the healthy server, got %q, %v", content, err)
+	}
+}
@@ -0,0 +1,227 @@
+package nacoshttp
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 长轮询协议中的分隔符
+const (
+	wordSeparator = "\x02"
+	lineSeparator = "\x01"
+)
+
+// listener 单个配置的监听
+type listener struct {
+	dataId    string
+	group     string
+	md5       string
+	nextID    int
+	callbacks map[int]func(string)
+}
+
+func listenKey(dataId, group string) string {
+	return group + wordSeparator + dataId
+}
+
+// Listen 监听配置变化，onChange 收到变化后的内容，配置被删除时为空字符串
+// 注册时读取当前内容作为基准，只通知之后的变化；所有监听共用一个长轮询连接
+func (c *Client) Listen(ctx context.Context, dataId, group string, onChange func(content string)) (cancel func(), err error) {
+	if c.ctx.Err() != nil {
+		return nil, ErrClosed
+	}
+	group = defaultGroup(group)
+	key := listenKey(dataId, group)
+
+	c.listenMu.Lock()
+	l, ok := c.listeners[key]
+	c.listenMu.Unlock()
+	if !ok {
+		content, err := c.GetConfig(ctx, dataId, group)
+		if err != nil {
+			return nil, err
+		}
+		l = &listener{dataId: dataId, group: group, md5: MD5(content), callbacks: make(map[int]func(string))}
+	}
+
+	c.listenMu.Lock()
+	if existing, ok := c.listeners[key]; ok {
+		l = existing
+	} else {
+		c.listeners[key] = l
+	}
+	id := l.nextID
+	l.nextID++
+	l.callbacks[id] = onChange
+	if !c.polling {
+		c.polling = true
+		go c.poll()
+	}
+	c.wakeLocked()
+	c.listenMu.Unlock()
+
+	return func() {
+		c.listenMu.Lock()
+		defer c.listenMu.Unlock()
+		delete(l.callbacks, id)
+		if len(l.callbacks) == 0 && c.listeners[key] == l {
+			delete(c.listeners, key)
+			c.wakeLocked()
+		}
+	}, nil
+}
+
+// wakeLocked 监听的配置变化后中断当前的长轮询，调用时需持有 listenMu
+func (c *Client) wakeLocked() {
+	if c.pollStop != nil {
+		c.pollStop()
+	}
+	select {
+	case c.pollWake <- struct{}{}:
+	default:
+	}
+}
+
+// poll 长轮询循环，直到客户端关闭
+func (c *Client) poll() {
+	defer close(c.done)
+
+	for {
+		c.listenMu.Lock()
+		if c.ctx.Err() != nil {
+			c.listenMu.Unlock()
+			return
+		}
+		if len(c.listeners) == 0 {
+			c.listenMu.Unlock()
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-c.pollWake:
+			}
+			continue
+		}
+
+		var sb strings.Builder
+		for _, l := range c.listeners {
+			sb.WriteString(l.dataId + wordSeparator + l.group + wordSeparator + l.md5)
+			if c.config.Namespace != "" {
+				sb.WriteString(wordSeparator + c.config.Namespace)
+			}
+			sb.WriteString(lineSeparator)
+		}
+		ctx, stop := context.WithCancel(c.ctx)
+		c.pollStop = stop
+		c.listenMu.Unlock()
+
+		changed, err := c.longPoll(ctx, sb.String())
+		woken := ctx.Err() != nil
+		stop()
+
+		switch {
+		case c.ctx.Err() != nil:
+			return
+		case woken:
+			// 监听的配置有变化，立即重新发起
+			continue
+		case err != nil:
+			c.reportError(err)
+			c.sleep(defaultRetryInterval)
+			continue
+		}
+
+		failed := false
+		for _, key := range changed {
+			if err := c.refresh(key); err != nil {
+				c.reportError(err)
+				failed = true
+			}
+		}
+		// 读取失败时md5未更新，服务端会立即再次返回变化，等待后再重试
+		if failed {
+			c.sleep(defaultRetryInterval)
+		}
+	}
+}
+
+// longPoll 发起一次长轮询，返回内容发生变化的配置
+func (c *Client) longPoll(ctx context.Context, listening string) ([]string, error) {
+	header := http.Header{"Long-Pulling-Timeout": {strconv.FormatInt(c.config.LongPollTimeout.Milliseconds(), 10)}}
+	form := url.Values{"Listening-Configs": {listening}}
+	body, status, err := c.do(ctx, http.MethodPost, "/v1/cs/configs/listener", form, header, c.config.LongPollTimeout+c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, &Error{StatusCode: status, Body: string(body)}
+	}
+
+	decoded, err := url.QueryUnescape(strings.TrimSpace(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, line := range strings.Split(decoded, lineSeparator) {
+		parts := strings.Split(line, wordSeparator)
+		if len(parts) < 2 {
+			continue
+		}
+		changed = append(changed, listenKey(parts[0], parts[1]))
+	}
+	return changed, nil
+}
+
+// refresh 读取变化的配置，md5 与上次不同时通知回调
+func (c *Client) refresh(key string) error {
+	c.listenMu.Lock()
+	l, ok := c.listeners[key]
+	c.listenMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	content, err := c.GetConfig(c.ctx, l.dataId, l.group)
+	if err != nil {
+		return err
+	}
+	sum := MD5(content)
+
+	c.listenMu.Lock()
+	if l.md5 == sum {
+		c.listenMu.Unlock()
+		return nil
+	}
+	l.md5 = sum
+	callbacks := make([]func(string), 0, len(l.callbacks))
+	for id := 0; id < l.nextID; id++ {
+		if cb, ok := l.callbacks[id]; ok {
+			callbacks = append(callbacks, cb)
+		}
+	}
+	c.listenMu.Unlock()
+
+	for _, cb := range callbacks {
+		cb(content)
+	}
+	return nil
+}
+
+func (c *Client) reportError(err error) {
+	if c.config.OnError != nil {
+		c.config.OnError(err)
+	}
+}
+
+// sleep 等待 d 或客户端关闭
+func (c *Client) sleep(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-c.ctx.Done():
+	case <-timer.C:
+	}
+}
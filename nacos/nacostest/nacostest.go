@@ -0,0 +1,448 @@
+// Package nacostest 提供基于内存的Nacos配置客户端，用于离线测试
+//
+// ConfigClient 实现了 nacos-sdk-go 的 config_client.IConfigClient，
+// 通过 nacos.WithConfigClient 注入后即可在没有Nacos服务端的情况下测试 NacosClient：
+//
+//	fake := nacostest.NewConfigClient()
+//	fake.Set("app.yaml", "DEFAULT_GROUP", "port: 8080")
+//	client, err := nacos.NewNacosClient(cfg, nacos.WithConfigClient(fake), nacos.WithHealthProbe(fake.Probe))
+package nacostest
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacoshttp"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// DefaultGroup 未指定group时使用的分组，与SDK一致
+const DefaultGroup = "DEFAULT_GROUP"
+
+// Op 可注入失败的操作
+type Op string
+
+// 支持注入失败的操作
+const (
+	OpGet     Op = "get"
+	OpPublish Op = "publish"
+	OpDelete  Op = "delete"
+	OpListen  Op = "listen"
+	OpSearch  Op = "search"
+	OpProbe   Op = "probe"
+)
+
+// ErrClosed 客户端关闭后调用返回的错误
+var ErrClosed = errors.New("nacostest: client closed")
+
+// ConfigClient 基于内存的配置客户端
+//
+// 与SDK行为保持一致：读取不存在的配置返回空字符串且无错误，删除配置会向监听者推送空内容。
+// 推送在调用 PublishConfig/DeleteConfig/Push 的goroutine中同步执行，便于测试断言。
+type ConfigClient struct {
+	mu        sync.Mutex
+	namespace string
+	nextID    int
+	configs   map[string]*configItem
+	listeners map[string]func(namespace, group, dataId, data string)
+	failures  map[Op]*failure
+	closed    bool
+}
+
+var _ config_client.IConfigClient = (*ConfigClient)(nil)
+
+// configItem 内存中的单条配置
+type configItem struct {
+	id         int
+	dataId     string
+	group      string
+	content    string
+	meta       configMeta
+	createTime time.Time
+	modifyTime time.Time
+}
+
+// configMeta 发布时附带的元数据
+type configMeta struct {
+	appName    string
+	tag        string
+	configType string
+	desc       string
+	configTags string
+	srcUser    string
+}
+
+// failure 注入的失败，times 为剩余次数，小于0表示一直失败
+type failure struct {
+	err   error
+	times int
+}
+
+// NewConfigClient 创建空的内存配置客户端
+func NewConfigClient() *ConfigClient {
+	return &ConfigClient{
+		configs:   make(map[string]*configItem),
+		listeners: make(map[string]func(namespace, group, dataId, data string)),
+		failures:  make(map[Op]*failure),
+	}
+}
+
+// WithNamespace 设置推送回调中携带的命名空间
+func (c *ConfigClient) WithNamespace(namespace string) *ConfigClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.namespace = namespace
+	return c
+}
+
+func configKey(dataId, group string) string {
+	return group + "@@" + dataId
+}
+
+func defaultGroup(group string) string {
+	if group == "" {
+		return DefaultGroup
+	}
+	return group
+}
+
+// Set 写入配置，不触发推送
+func (c *ConfigClient) Set(dataId, group, content string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(dataId, defaultGroup(group), content, configMeta{})
+}
+
+func (c *ConfigClient) set(dataId, group, content string, meta configMeta) {
+	key := configKey(dataId, group)
+	now := time.Now()
+	item, ok := c.configs[key]
+	if !ok {
+		c.nextID++
+		item = &configItem{id: c.nextID, dataId: dataId, group: group, createTime: now}
+		c.configs[key] = item
+	}
+	item.content = content
+	item.meta = meta
+	item.modifyTime = now
+}
+
+// Get 读取内存中的配置，不受注入的失败影响
+func (c *ConfigClient) Get(dataId, group string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.configs[configKey(dataId, defaultGroup(group))]
+	if !ok {
+		return "", false
+	}
+	return item.content, true
+}
+
+// Push 模拟服务端推送：写入配置并通知监听者
+func (c *ConfigClient) Push(dataId, group, content string) {
+	group = defaultGroup(group)
+	c.mu.Lock()
+	c.set(dataId, group, content, configMeta{})
+	c.mu.Unlock()
+	c.notify(dataId, group, content)
+}
+
+// Listening 返回是否存在对应 dataId/group 的监听
+func (c *ConfigClient) Listening(dataId, group string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.listeners[configKey(dataId, defaultGroup(group))]
+	return ok
+}
+
+// Fail 使指定操作一直返回 err，直到调用 Recover
+func (c *ConfigClient) Fail(op Op, err error) {
+	c.FailTimes(op, err, -1)
+}
+
+// FailTimes 使指定操作接下来的 times 次调用返回 err
+func (c *ConfigClient) FailTimes(op Op, err error, times int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures[op] = &failure{err: err, times: times}
+}
+
+// Recover 清除指定操作注入的失败，未指定操作时清除全部
+func (c *ConfigClient) Recover(ops ...Op) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(ops) == 0 {
+		c.failures = make(map[Op]*failure)
+		return
+	}
+	for _, op := range ops {
+		delete(c.failures, op)
+	}
+}
+
+// check 返回关闭或注入的失败，调用时需持有锁
+func (c *ConfigClient) check(op Op) error {
+	if c.closed {
+		return ErrClosed
+	}
+	f, ok := c.failures[op]
+	if !ok {
+		return nil
+	}
+	if f.times > 0 {
+		f.times--
+		if f.times == 0 {
+			delete(c.failures, op)
+		}
+	}
+	return f.err
+}
+
+// Probe 模拟服务端健康检查，可作为 nacos.WithHealthProbe 的参数
+func (c *ConfigClient) Probe(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.check(OpProbe)
+}
+
+// GetConfig 读取配置
+func (c *ConfigClient) GetConfig(param vo.ConfigParam) (string, error) {
+	if param.DataId == "" {
+		return "", errors.New("[client.GetConfig] param.dataId can not be empty")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(OpGet); err != nil {
+		return "", err
+	}
+	item, ok := c.configs[configKey(param.DataId, defaultGroup(param.Group))]
+	if !ok {
+		return "", nil
+	}
+	return item.content, nil
+}
+
+// PublishConfig 发布配置并通知监听者
+func (c *ConfigClient) PublishConfig(param vo.ConfigParam) (bool, error) {
+	if param.DataId == "" {
+		return false, errors.New("[client.PublishConfig] param.dataId can not be empty")
+	}
+	if param.Content == "" {
+		return false, errors.New("[client.PublishConfig] param.content can not be empty")
+	}
+
+	return c.publish(param.DataId, param.Group, param.Content, configMeta{
+		appName:    param.AppName,
+		tag:        param.Tag,
+		configType: param.Type,
+		configTags: param.ConfigTags,
+		srcUser:    param.SrcUser,
+	})
+}
+
+// PublishConfigWithMetadata 发布配置并记录描述等元数据，实现 nacos.MetadataConfigClient
+func (c *ConfigClient) PublishConfigWithMetadata(param nacoshttp.PublishParam) (bool, error) {
+	if param.DataId == "" {
+		return false, errors.New("[client.PublishConfig] param.dataId can not be empty")
+	}
+	if param.Content == "" {
+		return false, errors.New("[client.PublishConfig] param.content can not be empty")
+	}
+	return c.publish(param.DataId, param.Group, param.Content, configMeta{
+		appName:    param.AppName,
+		configType: param.Type,
+		desc:       param.Desc,
+		configTags: param.Tags,
+		srcUser:    param.SrcUser,
+	})
+}
+
+func (c *ConfigClient) publish(dataId, group, content string, meta configMeta) (bool, error) {
+	group = defaultGroup(group)
+	c.mu.Lock()
+	if err := c.check(OpPublish); err != nil {
+		c.mu.Unlock()
+		return false, err
+	}
+	c.set(dataId, group, content, meta)
+	c.mu.Unlock()
+
+	c.notify(dataId, group, content)
+	return true, nil
+}
+
+// GetConfigInfo 读取配置内容及元数据，配置不存在时返回nil，实现 nacos.MetadataConfigClient
+func (c *ConfigClient) GetConfigInfo(dataId, group string) (*nacoshttp.ConfigInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(OpGet); err != nil {
+		return nil, err
+	}
+	item, ok := c.configs[configKey(dataId, defaultGroup(group))]
+	if !ok {
+		return nil, nil
+	}
+	return &nacoshttp.ConfigInfo{
+		ID:         jsonNumber(item.id),
+		DataId:     item.dataId,
+		Group:      item.group,
+		Tenant:     c.namespace,
+		Content:    item.content,
+		MD5:        nacoshttp.MD5(item.content),
+		Type:       item.meta.configType,
+		AppName:    item.meta.appName,
+		Desc:       item.meta.desc,
+		Tags:       item.meta.configTags,
+		CreateUser: item.meta.srcUser,
+		CreateTime: item.createTime.UnixMilli(),
+		ModifyTime: item.modifyTime.UnixMilli(),
+	}, nil
+}
+
+// DeleteConfig 删除配置并向监听者推送空内容
+func (c *ConfigClient) DeleteConfig(param vo.ConfigParam) (bool, error) {
+	if param.DataId == "" {
+		return false, errors.New("[client.DeleteConfig] param.dataId can not be empty")
+	}
+
+	group := defaultGroup(param.Group)
+	key := configKey(param.DataId, group)
+	c.mu.Lock()
+	if err := c.check(OpDelete); err != nil {
+		c.mu.Unlock()
+		return false, err
+	}
+	_, existed := c.configs[key]
+	delete(c.configs, key)
+	c.mu.Unlock()
+
+	if existed {
+		c.notify(param.DataId, group, "")
+	}
+	return true, nil
+}
+
+// ListenConfig 注册监听，同一 dataId/group 只保留最后一个回调（与SDK一致）
+func (c *ConfigClient) ListenConfig(param vo.ConfigParam) error {
+	if param.DataId == "" {
+		return errors.New("[client.ListenConfig] param.dataId can not be empty")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(OpListen); err != nil {
+		return err
+	}
+	c.listeners[configKey(param.DataId, defaultGroup(param.Group))] = param.OnChange
+	return nil
+}
+
+// CancelListenConfig 取消监听
+func (c *ConfigClient) CancelListenConfig(param vo.ConfigParam) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.listeners, configKey(param.DataId, defaultGroup(param.Group)))
+	return nil
+}
+
+// SearchConfig 搜索配置，Search 为 blur 时 DataId/Group 支持 * 通配
+func (c *ConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	if param.Search != "accurate" && param.Search != "blur" {
+		return nil, errors.New("[client.searchConfigInner] param.search must be accurate or blur")
+	}
+	if param.PageNo <= 0 {
+		param.PageNo = 1
+	}
+	if param.PageSize <= 0 {
+		param.PageSize = 10
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.check(OpSearch); err != nil {
+		return nil, err
+	}
+
+	var items []*configItem
+	for _, item := range c.configs {
+		if match(param.Search, param.DataId, item.dataId) &&
+			match(param.Search, param.Group, item.group) &&
+			(param.Tag == "" || param.Tag == item.meta.tag) &&
+			(param.AppName == "" || param.AppName == item.meta.appName) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].id < items[j].id })
+
+	page := &model.ConfigPage{
+		TotalCount:     len(items),
+		PageNumber:     param.PageNo,
+		PagesAvailable: (len(items) + param.PageSize - 1) / param.PageSize,
+	}
+	start := (param.PageNo - 1) * param.PageSize
+	for i := start; i < len(items) && i < start+param.PageSize; i++ {
+		item := items[i]
+		sum := md5.Sum([]byte(item.content))
+		page.PageItems = append(page.PageItems, model.ConfigItem{
+			Id:      jsonNumber(item.id),
+			DataId:  item.dataId,
+			Group:   item.group,
+			Content: item.content,
+			Md5:     hex.EncodeToString(sum[:]),
+			Tenant:  c.namespace,
+			Appname: item.meta.appName,
+		})
+	}
+	return page, nil
+}
+
+// CloseClient 关闭客户端，之后的调用都返回 ErrClosed
+func (c *ConfigClient) CloseClient() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	c.listeners = make(map[string]func(namespace, group, dataId, data string))
+}
+
+// notify 通知 dataId/group 的监听者
+func (c *ConfigClient) notify(dataId, group, content string) {
+	c.mu.Lock()
+	listener := c.listeners[configKey(dataId, group)]
+	namespace := c.namespace
+	c.mu.Unlock()
+
+	if listener != nil {
+		listener(namespace, group, dataId, content)
+	}
+}
+
+// match 按搜索模式匹配，空模式匹配全部
+func match(search, pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	if search == "accurate" {
+		return pattern == value
+	}
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
+
+func jsonNumber(id int) json.Number {
+	return json.Number(strconv.Itoa(id))
+}
@@ -0,0 +1,144 @@
+package nacostest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos"
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+func newClient(t *testing.T, fake *nacostest.ConfigClient) *nacos.NacosClient {
+	t.Helper()
+	client, err := nacos.NewNacosClient(nacos.Config{
+		Nacos: nacos.NacosConfig{
+			Addr:   "127.0.0.1",
+			Port:   8848,
+			Dataid: "app.yaml",
+			Group:  "DEFAULT_GROUP",
+		},
+	}, nacos.WithConfigClient(fake), nacos.WithHealthProbe(fake.Probe))
+	if err != nil {
+		t.Fatalf("NewNacosClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestNacosClientOffline(t *testing.T) {
+	fake := nacostest.NewConfigClient()
+	fake.Set("app.yaml", "DEFAULT_GROUP", "port: 8080")
+	client := newClient(t, fake)
+	ctx := context.Background()
+
+	// dataId/group 为空时使用配置中的默认值
+	content, err := client.GetConfig(ctx, "", "")
+	if err != nil || content != "port: 8080" {
+		t.Fatalf("GetConfig() = %q, %v", content, err)
+	}
+
+	var pushed []string
+	if err := client.ListenConfig(ctx, "app.yaml", "DEFAULT_GROUP", func(content string) {
+		pushed = append(pushed, content)
+	}); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+	if !fake.Listening("app.yaml", "DEFAULT_GROUP") {
+		t.Fatal("Expected listener to be registered on the fake")
+	}
+
+	if err := client.PublishConfig(ctx, "app.yaml", "DEFAULT_GROUP", "port: 9090"); err != nil {
+		t.Fatalf("PublishConfig() error = %v", err)
+	}
+	fake.Push("app.yaml", "DEFAULT_GROUP", "port: 9091")
+	if err := client.DeleteConfig(ctx, "app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatalf("DeleteConfig() error = %v", err)
+	}
+
+	want := []string{"port: 9090", "port: 9091", ""}
+	if len(pushed) != len(want) {
+		t.Fatalf("Expected pushes %q, got %q", want, pushed)
+	}
+	for i := range want {
+		if pushed[i] != want[i] {
+			t.Errorf("push %d: expected %q, got %q", i, want[i], pushed[i])
+		}
+	}
+	if _, ok := fake.Get("app.yaml", "DEFAULT_GROUP"); ok {
+		t.Error("Expected config to be deleted")
+	}
+}
+
+func TestInjectedFailures(t *testing.T) {
+	fake := nacostest.NewConfigClient()
+	fake.Set("app.yaml", "DEFAULT_GROUP", "port: 8080")
+	client := newClient(t, fake)
+	ctx := context.Background()
+
+	boom := errors.New("server unavailable")
+	fake.FailTimes(nacostest.OpGet, boom, 1)
+	_, err := client.GetConfig(ctx, "app.yaml", "DEFAULT_GROUP")
+	if !errors.Is(err, boom) || nacos.ErrorCode(err) != nacos.ErrOperationFailed.Code {
+		t.Fatalf("Expected injected %s error, got %v", nacos.ErrOperationFailed.Code, err)
+	}
+	if _, err := client.GetConfig(ctx, "app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatalf("Expected failure to be consumed, got %v", err)
+	}
+
+	fake.Fail(nacostest.OpPublish, boom)
+	if err := client.PublishConfig(ctx, "app.yaml", "DEFAULT_GROUP", "port: 1"); nacos.ErrorCode(err) != nacos.ErrPublishFailed.Code {
+		t.Errorf("Expected %s, got %v", nacos.ErrPublishFailed.Code, err)
+	}
+	fake.Fail(nacostest.OpListen, boom)
+	if err := client.ListenConfig(ctx, "other.yaml", "DEFAULT_GROUP", nil); nacos.ErrorCode(err) != nacos.ErrListenFailed.Code {
+		t.Errorf("Expected %s, got %v", nacos.ErrListenFailed.Code, err)
+	}
+
+	fake.Fail(nacostest.OpProbe, boom)
+	if status := client.HealthCheck(ctx); status.Status != nacos.HealthDown || !status.ServingFromCache {
+		t.Errorf("Expected DOWN status serving from cache, got %+v", status)
+	}
+
+	fake.Recover()
+	if err := client.PublishConfig(ctx, "app.yaml", "DEFAULT_GROUP", "port: 1"); err != nil {
+		t.Errorf("PublishConfig() after Recover error = %v", err)
+	}
+	if status := client.HealthCheck(ctx); status.Status != nacos.HealthUp {
+		t.Errorf("Expected UP status after Recover, got %s", status.Status)
+	}
+}
+
+func TestSearchConfig(t *testing.T) {
+	fake := nacostest.NewConfigClient()
+	fake.Set("app.yaml", "DEFAULT_GROUP", "a: 1")
+	fake.Set("db.yaml", "DEFAULT_GROUP", "b: 2")
+	fake.Set("app.yaml", "PROD", "c: 3")
+
+	tests := []struct {
+		name  string
+		param vo.SearchConfigParam
+		want  int
+	}{
+		{"accurate", vo.SearchConfigParam{Search: "accurate", DataId: "app.yaml", Group: "PROD"}, 1},
+		{"blur dataId", vo.SearchConfigParam{Search: "blur", DataId: "app*"}, 2},
+		{"blur all", vo.SearchConfigParam{Search: "blur"}, 3},
+		{"paged", vo.SearchConfigParam{Search: "blur", PageNo: 2, PageSize: 2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := fake.SearchConfig(tt.param)
+			if err != nil {
+				t.Fatalf("SearchConfig() error = %v", err)
+			}
+			if len(page.PageItems) != tt.want {
+				t.Errorf("Expected %d items, got %d", tt.want, len(page.PageItems))
+			}
+		})
+	}
+
+	if _, err := fake.SearchConfig(vo.SearchConfigParam{Search: "fuzzy"}); err == nil {
+		t.Error("Expected invalid search mode to fail")
+	}
+}
@@ -0,0 +1,329 @@
+package nacos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// placeholderPattern 匹配 ${...} 形式的占位符
+var placeholderPattern = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+// nacosPlaceholderPrefix 引用其他dataId的占位符前缀
+const nacosPlaceholderPrefix = "nacos:"
+
+// WithInterpolation 开启配置内容的占位符解析
+//
+// 支持的占位符：
+//   - ${VAR}：环境变量，未设置时报错
+//   - ${VAR:default}：环境变量，未设置时使用默认值
+//   - ${nacos:dataId#key.path}：同分组下其他dataId中的配置项，可追加 :default
+//   - ${nacos:GROUP/dataId#key.path}：指定分组下其他dataId中的配置项
+//   - ${nacos:dataId}：其他dataId的完整内容
+//
+// 被引用的dataId按扩展名(yaml/json/properties/toml)解析，无扩展名时按yaml解析。
+// 监听配置时，被引用的dataId发生变化也会重新解析并触发回调。
+func WithInterpolation() Option {
+	return func(c *NacosClient) {
+		c.interpolation = true
+	}
+}
+
+// configRef 被引用的配置
+type configRef struct {
+	dataId string
+	group  string
+}
+
+func (r configRef) key() string {
+	return r.group + "@@" + r.dataId
+}
+
+// placeholderResolver 单次解析过程的状态
+type placeholderResolver struct {
+	ctx    context.Context
+	client *NacosClient
+	stack  []configRef
+	deps   map[string]configRef
+}
+
+// resolvePlaceholders 解析配置中的占位符，同时返回解析过程中引用到的全部dataId（含间接引用）
+func (c *NacosClient) resolvePlaceholders(ctx context.Context, dataId, group, content string) (string, map[string]configRef, error) {
+	r := &placeholderResolver{
+		ctx:    ctx,
+		client: c,
+		deps:   make(map[string]configRef),
+	}
+	resolved, err := r.resolve(configRef{dataId: dataId, group: group}, content)
+	return resolved, r.deps, err
+}
+
+// resolve 解析单个配置内容中的占位符
+func (r *placeholderResolver) resolve(ref configRef, content string) (string, error) {
+	for i, s := range r.stack {
+		if s.key() == ref.key() {
+			chain := make([]string, 0, len(r.stack)-i+1)
+			for _, c := range r.stack[i:] {
+				chain = append(chain, c.dataId)
+			}
+			chain = append(chain, ref.dataId)
+			return "", NewNacosError(ErrPlaceholderUnresolved.Code,
+				fmt.Sprintf("占位符循环引用: %s", strings.Join(chain, " -> ")), nil)
+		}
+	}
+	r.stack = append(r.stack, ref)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	var firstErr error
+	resolved := placeholderPattern.ReplaceAllStringFunc(content, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+		value, err := r.resolveExpr(ref, match[2:len(match)-1])
+		if err != nil {
+			firstErr = err
+			return match
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return resolved, nil
+}
+
+// resolveExpr 解析单个占位符表达式
+func (r *placeholderResolver) resolveExpr(current configRef, expr string) (string, error) {
+	if strings.HasPrefix(expr, nacosPlaceholderPrefix) {
+		return r.resolveNacos(current, expr)
+	}
+
+	name, def, hasDef := strings.Cut(expr, ":")
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	if hasDef {
+		return def, nil
+	}
+	return "", NewNacosError(ErrPlaceholderUnresolved.Code,
+		fmt.Sprintf("占位符无法解析: ${%s}，环境变量未设置 [DataId: %s]", expr, current.dataId), nil)
+}
+
+// resolveNacos 解析引用其他dataId的占位符
+func (r *placeholderResolver) resolveNacos(current configRef, expr string) (string, error) {
+	target, path, _ := strings.Cut(strings.TrimPrefix(expr, nacosPlaceholderPrefix), "#")
+	path, def, hasDef := strings.Cut(path, ":")
+
+	ref := configRef{dataId: target, group: current.group}
+	if group, dataId, ok := strings.Cut(target, "/"); ok {
+		ref = configRef{dataId: dataId, group: group}
+	}
+	if ref.dataId == "" {
+		return "", NewNacosError(ErrPlaceholderUnresolved.Code,
+			fmt.Sprintf("占位符格式错误: ${%s} [DataId: %s]", expr, current.dataId), nil)
+	}
+	r.deps[ref.key()] = ref
+
+	raw, err := r.client.fetchConfig(r.ctx, ref.dataId, ref.group)
+	if err != nil {
+		if hasDef {
+			return def, nil
+		}
+		return "", NewNacosError(ErrPlaceholderUnresolved.Code,
+			fmt.Sprintf("占位符无法解析: ${%s} [DataId: %s]", expr, current.dataId), err)
+	}
+
+	content, err := r.resolve(ref, raw)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return content, nil
+	}
+
+	value, ok, err := lookupConfigValue(ref.dataId, content, path)
+	if err != nil {
+		return "", NewNacosError(ErrPlaceholderUnresolved.Code,
+			fmt.Sprintf("占位符无法解析: ${%s} [DataId: %s]", expr, current.dataId), err)
+	}
+	if !ok {
+		if hasDef {
+			return def, nil
+		}
+		return "", NewNacosError(ErrPlaceholderUnresolved.Code,
+			fmt.Sprintf("占位符无法解析: ${%s}，%s 中不存在 %s [DataId: %s]", expr, ref.dataId, path, current.dataId), nil)
+	}
+	return value, nil
+}
+
+// lookupConfigValue 按dataId扩展名解析配置内容，读取指定路径的值
+func lookupConfigValue(dataId, content, path string) (string, bool, error) {
+	configType := configTypeOf(dataId)
+	if configType == "properties" {
+		props, err := parseProperties(content)
+		if err != nil {
+			return "", false, fmt.Errorf("解析配置失败 [DataId: %s]: %w", dataId, err)
+		}
+		value, ok := props[path]
+		return value, ok, nil
+	}
+
+	v := viper.New()
+	v.SetConfigType(configType)
+	if err := v.ReadConfig(strings.NewReader(content)); err != nil {
+		return "", false, fmt.Errorf("解析配置失败 [DataId: %s]: %w", dataId, err)
+	}
+	if !v.IsSet(path) {
+		return "", false, nil
+	}
+
+	switch value := v.Get(path).(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", false, err
+		}
+		return string(data), true, nil
+	default:
+		return fmt.Sprint(value), true, nil
+	}
+}
+
+// configTypeOf 根据dataId扩展名推断配置格式，无法识别时按yaml处理
+func configTypeOf(dataId string) string {
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(dataId), ".")); ext {
+	case "yml":
+		return "yaml"
+	case "props":
+		return "properties"
+	case "yaml", "json", "properties", "toml":
+		return ext
+	default:
+		return "yaml"
+	}
+}
+
+// placeholderWatch 带占位符解析的监听，被引用的dataId变化时重新解析
+type placeholderWatch struct {
+	client   *NacosClient
+	ref      configRef
+	callback func(string) error
+
+	mu     sync.Mutex
+	raw    string
+	last   string
+	deps   map[string]func()
+	cancel func()
+}
+
+// watchPlaceholders 注册带占位符解析的监听回调
+func (c *NacosClient) watchPlaceholders(dataId, group string, handler func(string) error) (func(), error) {
+	w := &placeholderWatch{
+		client:   c,
+		ref:      configRef{dataId: dataId, group: group},
+		callback: handler,
+		deps:     make(map[string]func()),
+	}
+
+	cancel, err := c.watchConfig(dataId, group, w.onChange)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	w.cancel = cancel
+	// 预先解析一次以确定需要监听的引用
+	if raw, err := c.fetchConfig(context.Background(), dataId, group); err == nil {
+		w.raw = raw
+		w.refreshLocked(false)
+	}
+	w.mu.Unlock()
+
+	return w.close, nil
+}
+
+// onChange 配置本身发生变化，返回解析失败或回调拒绝的原因
+func (w *placeholderWatch) onChange(raw string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.raw = raw
+	return w.refreshLocked(true)
+}
+
+// onDependencyChange 被引用的配置发生变化
+// 引用方解析或校验失败不影响被引用配置本身的推送结果，只记录日志
+func (w *placeholderWatch) onDependencyChange(string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.refreshLocked(true)
+	return nil
+}
+
+// refreshLocked 重新解析并更新引用监听，内容变化且notify为true时触发回调
+func (w *placeholderWatch) refreshLocked(notify bool) error {
+	if w.cancel == nil {
+		return nil
+	}
+
+	resolved, deps, err := w.client.resolvePlaceholders(context.Background(), w.ref.dataId, w.ref.group, w.raw)
+	w.syncDependencies(deps)
+	if err != nil {
+		w.client.clientLogger().Error("解析配置占位符失败", w.client.logFields(w.ref.dataId, w.ref.group, err)...)
+		return err
+	}
+
+	if resolved == w.last {
+		return nil
+	}
+	if notify && w.callback != nil {
+		// 被拒绝的内容不作为上次投递的内容，再次推送时重新校验
+		if err := w.callback(resolved); err != nil {
+			return err
+		}
+	}
+	w.last = resolved
+	return nil
+}
+
+// syncDependencies 使引用监听与最新的引用集合保持一致
+func (w *placeholderWatch) syncDependencies(deps map[string]configRef) {
+	for key, cancel := range w.deps {
+		if _, ok := deps[key]; !ok {
+			cancel()
+			delete(w.deps, key)
+		}
+	}
+	for key, ref := range deps {
+		if _, ok := w.deps[key]; ok || key == w.ref.key() {
+			continue
+		}
+		cancel, err := w.client.watchConfig(ref.dataId, ref.group, w.onDependencyChange)
+		if err != nil {
+			w.client.clientLogger().Error("监听被引用配置失败", w.client.logFields(ref.dataId, ref.group, err)...)
+			continue
+		}
+		w.deps[key] = cancel
+	}
+}
+
+// close 取消监听
+func (w *placeholderWatch) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.cancel = nil
+	for key, cancel := range w.deps {
+		cancel()
+		delete(w.deps, key)
+	}
+}
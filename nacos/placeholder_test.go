@@ -0,0 +1,108 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// newTestClient 创建使用指定配置客户端的测试客户端，默认 dataId 为 app.yaml
+func newTestClient(sdk config_client.IConfigClient, opts ...Option) *NacosClient {
+	c := &NacosClient{
+		client: sdk,
+		config: &Config{Nacos: NacosConfig{Dataid: "app.yaml", Group: "DEFAULT_GROUP"}},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func TestPlaceholderResolve(t *testing.T) {
+	t.Setenv("APP_ENV", "prod")
+
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("common.yaml", "DEFAULT_GROUP", "db:\n  host: 10.0.0.1\n  port: 3306\n")
+	sdk.Set("redis.properties", "SHARED", "redis.addr=10.0.0.2:6379\n")
+	sdk.Set("app.yaml", "DEFAULT_GROUP", "env: ${APP_ENV}\n"+
+		"region: ${APP_REGION:cn-east}\n"+
+		"dsn: ${nacos:common.yaml#db.host}:${nacos:common.yaml#db.port}\n"+
+		"redis: ${nacos:SHARED/redis.properties#redis.addr}\n"+
+		"timeout: ${nacos:common.yaml#db.timeout:30s}\n")
+
+	client := newTestClient(sdk, WithInterpolation())
+	content, err := client.GetConfig(context.Background(), "app.yaml", "DEFAULT_GROUP")
+	if err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+
+	expected := "env: prod\nregion: cn-east\ndsn: 10.0.0.1:3306\nredis: 10.0.0.2:6379\ntimeout: 30s\n"
+	if content != expected {
+		t.Errorf("Expected %q, got %q", expected, content)
+	}
+}
+
+func TestPlaceholderErrors(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("unresolved.yaml", "DEFAULT_GROUP", "value: ${NACOS_TEST_UNSET_VAR}")
+	sdk.Set("a.yaml", "DEFAULT_GROUP", "value: ${nacos:b.yaml#value}")
+	sdk.Set("b.yaml", "DEFAULT_GROUP", "value: ${nacos:a.yaml#value}")
+
+	client := newTestClient(sdk, WithInterpolation())
+
+	for _, dataId := range []string{"unresolved.yaml", "a.yaml"} {
+		_, err := client.GetConfig(context.Background(), dataId, "DEFAULT_GROUP")
+		var nacosErr *NacosError
+		if !errors.As(err, &nacosErr) || nacosErr.Code != ErrPlaceholderUnresolved.Code {
+			t.Errorf("%s: expected %s error, got %v", dataId, ErrPlaceholderUnresolved.Code, err)
+		}
+	}
+}
+
+func TestPlaceholderListenReResolve(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("common.yaml", "DEFAULT_GROUP", "host: a")
+	sdk.Set("app.yaml", "DEFAULT_GROUP", "host: ${nacos:common.yaml#host}")
+
+	client := newTestClient(sdk, WithInterpolation())
+
+	var got []string
+	err := client.ListenConfig(context.Background(), "app.yaml", "DEFAULT_GROUP", func(content string) {
+		got = append(got, content)
+	})
+	if err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+
+	// 被引用的配置变化时重新解析
+	sdk.PublishConfig(vo.ConfigParam{DataId: "common.yaml", Group: "DEFAULT_GROUP", Content: "host: b"})
+	// 配置本身变化
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: "addr: ${nacos:common.yaml#host}"})
+
+	expected := []string{"host: b", "addr: b"}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], got[i])
+		}
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	props, err := parseProperties("# comment\na=1\nb : 2\nc 3\nd=line1\\\n  line2\n")
+	if err != nil {
+		t.Fatalf("parseProperties() error = %v", err)
+	}
+	expected := map[string]string{"a": "1", "b": "2", "c": "3", "d": "line1line2"}
+	for k, v := range expected {
+		if props[k] != v {
+			t.Errorf("Expected %s = %q, got %q", k, v, props[k])
+		}
+	}
+}
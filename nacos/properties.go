@@ -0,0 +1,94 @@
+package nacos
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// parseProperties 解析 .properties 格式的配置内容
+// 支持 # 与 ! 注释、= 与 : 分隔符以及以 \ 结尾的续行，返回出错的行号
+func parseProperties(content string) (map[string]string, error) {
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+
+	lineNo := 0
+	startLine, startColumn := 0, 0
+	var logical strings.Builder
+	for scanner.Scan() {
+		lineNo++
+		raw := scanner.Text()
+		line := strings.TrimLeft(raw, " \t\f")
+
+		if logical.Len() == 0 {
+			if line == "" || line[0] == '#' || line[0] == '!' {
+				continue
+			}
+			startLine, startColumn = lineNo, len(raw)-len(line)+1
+		}
+
+		// 奇数个反斜杠结尾表示续行
+		trailing := len(line) - len(strings.TrimRight(line, `\`))
+		if trailing%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+
+		key, value, err := splitProperty(logical.String())
+		if err != nil {
+			return nil, &propertiesError{Line: startLine, Column: startColumn, Err: err}
+		}
+		result[key] = value
+		logical.Reset()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if logical.Len() > 0 {
+		return nil, &propertiesError{Line: startLine, Column: startColumn, Err: fmt.Errorf("续行未结束")}
+	}
+	return result, nil
+}
+
+// splitProperty 拆分单个属性的键和值
+func splitProperty(line string) (string, string, error) {
+	idx := strings.IndexAny(line, "=: \t")
+	if idx < 0 {
+		return unescapeProperty(line), "", nil
+	}
+	key := line[:idx]
+	if key == "" {
+		return "", "", fmt.Errorf("属性名不能为空")
+	}
+	rest := strings.TrimLeft(line[idx:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return unescapeProperty(key), unescapeProperty(rest), nil
+}
+
+// unescapeProperty 处理常见的转义字符
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	replacer := strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t", `\r`, "\r", `\=`, "=", `\:`, ":", `\ `, " ")
+	return replacer.Replace(s)
+}
+
+// propertiesError properties解析错误，Line、Column 为出错属性的起始位置
+type propertiesError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *propertiesError) Error() string {
+	return fmt.Sprintf("第%d行: %v", e.Line, e.Err)
+}
+
+func (e *propertiesError) Unwrap() error {
+	return e.Err
+}
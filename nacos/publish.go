@@ -0,0 +1,260 @@
+package nacos
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacoshttp"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 控制台支持的配置类型
+const (
+	ConfigTypeText       = "text"
+	ConfigTypeJSON       = "json"
+	ConfigTypeXML        = "xml"
+	ConfigTypeYAML       = "yaml"
+	ConfigTypeHTML       = "html"
+	ConfigTypeProperties = "properties"
+)
+
+// PublishOptions 发布配置时附带的元数据
+type PublishOptions struct {
+	// Type 配置类型，为空时按dataId扩展名推断，无法推断时为 text
+	Type string
+	// AppName 所属应用
+	AppName string
+	// Desc 配置描述
+	Desc string
+	// Tags 配置标签
+	Tags []string
+	// SrcUser 发布人
+	SrcUser string
+}
+
+// ConfigMetadata 配置的元数据
+type ConfigMetadata struct {
+	DataId     string
+	Group      string
+	Namespace  string
+	Type       string
+	AppName    string
+	Desc       string
+	Tags       []string
+	MD5        string
+	CreateUser string
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+// MetadataConfigClient 配置客户端的可选接口，支持发布时附带描述以及读取配置元数据
+//
+// SDK的 ConfigParam 没有描述字段，也没有读取元数据的接口：
+// 配置客户端未实现该接口时，这两项操作通过 open API 完成。
+// http 后端与 nacostest.ConfigClient 实现了该接口。
+type MetadataConfigClient interface {
+	PublishConfigWithMetadata(param nacoshttp.PublishParam) (bool, error)
+	// GetConfigInfo 配置不存在时返回nil
+	GetConfigInfo(dataId, group string) (*nacoshttp.ConfigInfo, error)
+}
+
+// PublishConfigWithOptions 发布配置并附带类型、应用、描述、标签等元数据
+func (c *NacosClient) PublishConfigWithOptions(ctx context.Context, dataId, group, content string, opts PublishOptions) (err error) {
+	if c == nil || c.client == nil {
+		return fmt.Errorf("Nacos客户端未初始化")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// 使用默认值如果参数为空
+	if dataId == "" {
+		dataId = c.config.Nacos.Dataid
+	}
+	if group == "" {
+		group = c.config.Nacos.Group
+	}
+
+	ctx, span := c.startSpan(ctx, "PublishConfig", dataId, group, trace.WithAttributes(attrContentSize.Int(len(content))))
+	defer func() { endSpan(span, err) }()
+
+	start := time.Now()
+	defer func() { c.metrics.observe(opPublish, dataId, group, start, err) }()
+
+	configType, err := publishType(dataId, opts.Type)
+	if err != nil {
+		return err
+	}
+	if err := checkSyntax(dataId, group, syntaxType(dataId, configType, opts.Type != ""), content); err != nil {
+		return err
+	}
+
+	if err := c.validateContent(dataId, group, content); err != nil {
+		return err
+	}
+
+	content, err = c.encryptContent(ctx, dataId, content)
+	if err != nil {
+		return err
+	}
+
+	param := vo.ConfigParam{
+		DataId:     dataId,
+		Group:      group,
+		Content:    content,
+		Type:       configType,
+		AppName:    opts.AppName,
+		ConfigTags: joinTags(opts.Tags),
+		SrcUser:    opts.SrcUser,
+	}
+	var success bool
+	if opts.Desc == "" {
+		success, err = c.client.PublishConfig(param)
+	} else {
+		success, err = c.publishWithDesc(param, opts.Desc)
+	}
+	if err != nil {
+		return NewNacosError(ErrPublishFailed.Code, fmt.Sprintf("发布配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+	}
+
+	if !success {
+		return NewNacosError(ErrPublishFailed.Code, "发布配置失败，返回false", nil)
+	}
+
+	return nil
+}
+
+// publishWithDesc 发布带描述的配置
+func (c *NacosClient) publishWithDesc(param vo.ConfigParam, desc string) (bool, error) {
+	client, err := c.metadataClient()
+	if err != nil {
+		return false, err
+	}
+	return client.PublishConfigWithMetadata(nacoshttp.PublishParam{
+		DataId:  param.DataId,
+		Group:   param.Group,
+		Content: param.Content,
+		Type:    param.Type,
+		AppName: param.AppName,
+		Desc:    desc,
+		Tags:    param.ConfigTags,
+		SrcUser: param.SrcUser,
+	})
+}
+
+// GetConfigMetadata 获取配置的元数据，配置不存在时返回 CONFIG_NOT_FOUND
+func (c *NacosClient) GetConfigMetadata(ctx context.Context, dataId, group string) (metadata *ConfigMetadata, err error) {
+	if c == nil || c.client == nil {
+		return nil, fmt.Errorf("Nacos客户端未初始化")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// 使用默认值如果参数为空
+	if dataId == "" {
+		dataId = c.config.Nacos.Dataid
+	}
+	if group == "" {
+		group = c.config.Nacos.Group
+	}
+
+	_, span := c.startSpan(ctx, "GetConfigMetadata", dataId, group)
+	defer func() { endSpan(span, err) }()
+
+	client, err := c.metadataClient()
+	if err != nil {
+		return nil, NewNacosError(ErrConfigLoadFailed.Code, "创建open API客户端失败", err)
+	}
+	info, err := client.GetConfigInfo(dataId, group)
+	if err != nil {
+		return nil, NewNacosError(ErrConfigLoadFailed.Code, fmt.Sprintf("获取配置元数据失败 [DataId: %s, Group: %s]", dataId, group), err)
+	}
+	if info == nil {
+		return nil, NewNacosError(ErrConfigNotFound.Code, fmt.Sprintf("配置不存在 [DataId: %s, Group: %s]", dataId, group), nil)
+	}
+
+	metadata = &ConfigMetadata{
+		DataId:     info.DataId,
+		Group:      info.Group,
+		Namespace:  info.Tenant,
+		Type:       info.Type,
+		AppName:    info.AppName,
+		Desc:       info.Desc,
+		Tags:       splitTags(info.Tags),
+		MD5:        info.MD5,
+		CreateUser: info.CreateUser,
+	}
+	if info.CreateTime > 0 {
+		metadata.CreateTime = time.UnixMilli(info.CreateTime)
+	}
+	if info.ModifyTime > 0 {
+		metadata.ModifyTime = time.UnixMilli(info.ModifyTime)
+	}
+	return metadata, nil
+}
+
+// metadataClient 返回支持元数据的客户端，配置客户端未实现 MetadataConfigClient 时按配置创建open API客户端
+func (c *NacosClient) metadataClient() (MetadataConfigClient, error) {
+	if client, ok := c.client.(MetadataConfigClient); ok {
+		return client, nil
+	}
+	c.openAPIOnce.Do(func() {
+		c.openAPI, c.openAPIErr = newHTTPConfigClient(*c.config)
+	})
+	if c.openAPIErr != nil {
+		return nil, c.openAPIErr
+	}
+	return c.openAPI, nil
+}
+
+// publishType 校验配置类型，为空时按dataId扩展名推断
+func publishType(dataId, configType string) (string, error) {
+	if configType == "" {
+		switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(dataId), ".")); ext {
+		case "yaml", "yml":
+			return ConfigTypeYAML, nil
+		case "properties", "props":
+			return ConfigTypeProperties, nil
+		case "htm":
+			return ConfigTypeHTML, nil
+		case ConfigTypeJSON, ConfigTypeXML, ConfigTypeHTML:
+			return ext, nil
+		default:
+			return ConfigTypeText, nil
+		}
+	}
+
+	switch lower := strings.ToLower(configType); lower {
+	case ConfigTypeText, ConfigTypeJSON, ConfigTypeXML, ConfigTypeYAML, ConfigTypeHTML, ConfigTypeProperties:
+		return lower, nil
+	default:
+		return "", NewNacosError(ErrConfigInvalid.Code, fmt.Sprintf("不支持的配置类型: %s", configType), nil)
+	}
+}
+
+// joinTags 去除空白和空标签后以逗号连接
+func joinTags(tags []string) string {
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
+
+// splitTags 拆分服务端返回的逗号分隔标签
+func splitTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package nacos
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
+)
+
+var _ MetadataConfigClient = (*nacostest.ConfigClient)(nil)
+
+func TestPublishType(t *testing.T) {
+	tests := []struct {
+		dataId, configType, want string
+	}{
+		{"app.yaml", "", ConfigTypeYAML},
+		{"app.yml", "", ConfigTypeYAML},
+		{"db.json", "", ConfigTypeJSON},
+		{"log4j.xml", "", ConfigTypeXML},
+		{"app.props", "", ConfigTypeProperties},
+		{"index.htm", "", ConfigTypeHTML},
+		{"app.toml", "", ConfigTypeText},
+		{"banner", "", ConfigTypeText},
+		{"app.yaml", "JSON", ConfigTypeJSON},
+	}
+	for _, tt := range tests {
+		got, err := publishType(tt.dataId, tt.configType)
+		if err != nil || got != tt.want {
+			t.Errorf("publishType(%q, %q) = %q, %v, want %q", tt.dataId, tt.configType, got, err, tt.want)
+		}
+	}
+
+	if _, err := publishType("app.yaml", "ini"); ErrorCode(err) != ErrConfigInvalid.Code {
+		t.Errorf("Expected CONFIG_INVALID for unknown type, got %v", err)
+	}
+}
+
+func TestPublishConfigWithOptions(t *testing.T) {
+	fake := nacostest.NewConfigClient().WithNamespace("prod")
+	client := newTestClient(fake)
+	ctx := context.Background()
+
+	err := client.PublishConfigWithOptions(ctx, "", "", "port: 8080", PublishOptions{
+		AppName: "order",
+		Desc:    "订单服务",
+		Tags:    []string{"core", " ", "db "},
+		SrcUser: "ci",
+	})
+	if err != nil {
+		t.Fatalf("PublishConfigWithOptions() error = %v", err)
+	}
+
+	metadata, err := client.GetConfigMetadata(ctx, "", "")
+	if err != nil {
+		t.Fatalf("GetConfigMetadata() error = %v", err)
+	}
+	if metadata.DataId != "app.yaml" || metadata.Group != "DEFAULT_GROUP" || metadata.Namespace != "prod" ||
+		metadata.Type != ConfigTypeYAML || metadata.AppName != "order" || metadata.Desc != "订单服务" ||
+		!reflect.DeepEqual(metadata.Tags, []string{"core", "db"}) || metadata.CreateUser != "ci" {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+	if metadata.CreateTime.IsZero() || metadata.ModifyTime.Before(metadata.CreateTime) {
+		t.Errorf("Unexpected timestamps: %v, %v", metadata.CreateTime, metadata.ModifyTime)
+	}
+
+	// PublishConfig 只推断类型，不保留之前的元数据
+	if err := client.PublishConfig(ctx, "db.json", "", `{"port": 3306}`); err != nil {
+		t.Fatalf("PublishConfig() error = %v", err)
+	}
+	if metadata, _ := client.GetConfigMetadata(ctx, "db.json", ""); metadata == nil || metadata.Type != ConfigTypeJSON {
+		t.Errorf("Expected inferred json type, got %+v", metadata)
+	}
+
+	if err := client.PublishConfigWithOptions(ctx, "", "", "port: 1", PublishOptions{Type: "ini"}); ErrorCode(err) != ErrConfigInvalid.Code {
+		t.Errorf("Expected CONFIG_INVALID for unknown type, got %v", err)
+	}
+	if _, err := client.GetConfigMetadata(ctx, "missing.yaml", ""); ErrorCode(err) != ErrConfigNotFound.Code {
+		t.Errorf("Expected CONFIG_NOT_FOUND, got %v", err)
+	}
+}
+
+func TestPublishDescThroughOpenAPI(t *testing.T) {
+	host, port := newOpenAPIServer(t)
+	sdk := nacostest.NewConfigClient()
+	// 只暴露SDK接口，模拟不支持元数据的配置客户端
+	client := newTestClient(struct{ config_client.IConfigClient }{sdk})
+	client.config.Nacos.Addr = host
+	client.config.Nacos.Port = port
+	defer client.Close()
+	ctx := context.Background()
+
+	// 不带描述时使用SDK客户端
+	if err := client.PublishConfigWithOptions(ctx, "", "", "a: 1", PublishOptions{AppName: "order"}); err != nil {
+		t.Fatalf("PublishConfigWithOptions() error = %v", err)
+	}
+	if content, _ := sdk.Get("app.yaml", ""); content != "a: 1" {
+		t.Errorf("Expected publish without desc to use the sdk client, got %q", content)
+	}
+
+	// SDK不支持描述，改用open API
+	if err := client.PublishConfigWithOptions(ctx, "", "", "a: 2", PublishOptions{Desc: "描述", Tags: []string{"core"}}); err != nil {
+		t.Fatalf("PublishConfigWithOptions() error = %v", err)
+	}
+	metadata, err := client.GetConfigMetadata(ctx, "", "")
+	if err != nil {
+		t.Fatalf("GetConfigMetadata() error = %v", err)
+	}
+	if metadata.Desc != "描述" || metadata.Type != ConfigTypeYAML || !reflect.DeepEqual(metadata.Tags, []string{"core"}) {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+}
@@ -0,0 +1,247 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// ActionDelete 线上存在但目录中没有的配置，开启 Prune 时删除
+const ActionDelete ChangeAction = "delete"
+
+// searchPageSize 列出线上配置时每页的数量
+const searchPageSize = 100
+
+// Reconciler 将目录中的配置同步到Nacos
+//
+// 目录结构为 <namespace>/<group>/<dataId>，namespace 为 Manager 中的客户端名称（见“多命名空间”），
+// 以 . 开头的文件和目录被忽略，根目录下的文件（如README）也被忽略。
+type Reconciler struct {
+	Manager *Manager
+	Dir     string
+	// Prune 删除线上存在但目录中没有的配置，只处理目录中出现的 namespace/group
+	Prune bool
+	// Protected 受保护配置的匹配模式（path.Match 语法），匹配 "namespace/group/dataId"，
+	// 如 "prod/*/*" 或 "*/DEFAULT_GROUP/bootstrap.yaml"；受保护的配置不会被发布或删除
+	Protected []string
+	// DryRun 只比较并输出diff，不修改线上配置
+	DryRun bool
+	// Output 不为nil时写入每个有变化的配置的diff
+	Output io.Writer
+}
+
+// treeConfig 目录中的单个配置
+type treeConfig struct {
+	namespace string
+	group     string
+	dataId    string
+	content   string
+}
+
+// Plan 比较目录与线上配置，返回每个配置的动作
+func (r *Reconciler) Plan(ctx context.Context) ([]ConfigChange, error) {
+	if r.Manager == nil {
+		return nil, NewNacosError(ErrClientNotInit.Code, "Reconciler 未设置 Manager", nil)
+	}
+	for _, pattern := range r.Protected {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("受保护配置的模式无效 [%s]: %w", pattern, err)
+		}
+	}
+
+	configs, err := readConfigTree(r.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// namespace -> group -> dataId
+	desired := make(map[string]map[string]map[string]string)
+	for _, config := range configs {
+		if desired[config.namespace] == nil {
+			desired[config.namespace] = make(map[string]map[string]string)
+		}
+		if desired[config.namespace][config.group] == nil {
+			desired[config.namespace][config.group] = make(map[string]string)
+		}
+		desired[config.namespace][config.group][config.dataId] = config.content
+	}
+
+	var changes []ConfigChange
+	for _, namespace := range sortedNames(desired) {
+		client, err := r.Manager.Client(namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range sortedNames(desired[namespace]) {
+			files := desired[namespace][group]
+			for _, dataId := range sortedNames(files) {
+				current, err := client.liveContent(ctx, dataId, group)
+				if err != nil {
+					return nil, err
+				}
+				change := newConfigChange(namespace, dataId, group, current, files[dataId], client, PublishOptions{})
+				change.Protected = r.protected(namespace, group, dataId)
+				changes = append(changes, change)
+			}
+
+			if !r.Prune {
+				continue
+			}
+			live, err := client.listDataIds(ctx, group)
+			if err != nil {
+				return nil, err
+			}
+			for _, dataId := range live {
+				if _, ok := files[dataId]; ok {
+					continue
+				}
+				current, err := client.liveContent(ctx, dataId, group)
+				if err != nil {
+					return nil, err
+				}
+				name := namespace + "/" + group + "/" + dataId
+				changes = append(changes, ConfigChange{
+					Env:       namespace,
+					DataId:    dataId,
+					Group:     group,
+					Action:    ActionDelete,
+					Current:   current,
+					Diff:      unifiedDiff(name+" (线上)", name, current, ""),
+					Protected: r.protected(namespace, group, dataId),
+					client:    client,
+				})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// Reconcile 比较并应用变更，DryRun 时只比较
+// 先发布新增和更新的配置，再删除多余的配置；单个配置失败不影响其他配置，错误合并后返回
+func (r *Reconciler) Reconcile(ctx context.Context) ([]ConfigChange, error) {
+	changes, err := r.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.Output != nil {
+		writeChanges(r.Output, changes)
+	}
+	if r.DryRun {
+		return changes, nil
+	}
+
+	var errs []error
+	for _, change := range changes {
+		if change.Protected || (change.Action != ActionCreate && change.Action != ActionUpdate) {
+			continue
+		}
+		if err := change.client.PublishConfig(ctx, change.DataId, change.Group, change.Desired); err != nil {
+			errs = append(errs, fmt.Errorf("命名空间 %s: %w", change.Env, err))
+		}
+	}
+	for _, change := range changes {
+		if change.Protected || change.Action != ActionDelete {
+			continue
+		}
+		if err := change.client.DeleteConfig(ctx, change.DataId, change.Group); err != nil {
+			errs = append(errs, fmt.Errorf("命名空间 %s: %w", change.Env, err))
+		}
+	}
+	return changes, errors.Join(errs...)
+}
+
+// protected 返回配置是否匹配受保护的模式
+func (r *Reconciler) protected(namespace, group, dataId string) bool {
+	name := namespace + "/" + group + "/" + dataId
+	for _, pattern := range r.Protected {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readConfigTree 读取 <namespace>/<group>/<dataId> 结构的目录
+func readConfigTree(dir string) ([]treeConfig, error) {
+	var configs []treeConfig
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		switch len(parts) {
+		case 1:
+			return nil
+		case 3:
+		default:
+			return fmt.Errorf("配置文件 %s 不符合 <namespace>/<group>/<dataId> 结构", rel)
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("配置文件 %s 为空，Nacos不支持发布空内容", rel)
+		}
+		configs = append(configs, treeConfig{namespace: parts[0], group: parts[1], dataId: parts[2], content: string(data)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取配置目录失败: %w", err)
+	}
+	return configs, nil
+}
+
+// listDataIds 列出group下线上存在的全部dataId
+func (c *NacosClient) listDataIds(ctx context.Context, group string) ([]string, error) {
+	var dataIds []string
+	for pageNo := 1; ; pageNo++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := c.client.SearchConfig(vo.SearchConfigParam{
+			Search:   "blur",
+			Group:    group,
+			PageNo:   pageNo,
+			PageSize: searchPageSize,
+		})
+		if err != nil {
+			return nil, NewNacosError(ErrOperationFailed.Code, fmt.Sprintf("列出配置失败 [Group: %s]", group), err)
+		}
+		for _, item := range page.PageItems {
+			if item.Group == group {
+				dataIds = append(dataIds, item.DataId)
+			}
+		}
+		if len(page.PageItems) == 0 || pageNo >= page.PagesAvailable {
+			break
+		}
+	}
+	sort.Strings(dataIds)
+	return dataIds, nil
+}
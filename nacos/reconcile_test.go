@@ -0,0 +1,163 @@
+package nacos
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReconciler(t *testing.T) {
+	dev, prod := nacostest.NewConfigClient(), nacostest.NewConfigClient()
+	dev.Set("app.yaml", "DEFAULT_GROUP", "port: 8080\n")
+	dev.Set("old.yaml", "DEFAULT_GROUP", "stale: true\n")
+	dev.Set("keep.yaml", "DEFAULT_GROUP", "keep: true\n")
+	dev.Set("other.yaml", "OTHER", "untouched: true\n")
+	prod.Set("app.yaml", "DEFAULT_GROUP", "port: 80\n")
+	prod.Set("db.yaml", "DEFAULT_GROUP", "host: prod-db\n")
+
+	manager := &Manager{clients: map[string]*NacosClient{
+		"dev":  newTestClient(dev),
+		"prod": newTestClient(prod),
+	}}
+	dir := writeTree(t, map[string]string{
+		"README.md":                    "ignored",
+		".git/config":                  "ignored",
+		"dev/DEFAULT_GROUP/app.yaml":   "port: 8080\n",
+		"dev/DEFAULT_GROUP/new.yaml":   "created: true\n",
+		"dev/DEFAULT_GROUP/.swp":       "ignored",
+		"prod/DEFAULT_GROUP/app.yaml":  "port: 443\n",
+		"prod/DEFAULT_GROUP/db.yaml":   "host: new-db\n",
+		"prod/SHARED/redis.properties": "addr=10.0.0.1\n",
+	})
+
+	var out strings.Builder
+	reconciler := &Reconciler{
+		Manager:   manager,
+		Dir:       dir,
+		Prune:     true,
+		Protected: []string{"*/*/keep.yaml", "prod/DEFAULT_GROUP/db.yaml"},
+		DryRun:    true,
+		Output:    &out,
+	}
+	ctx := context.Background()
+
+	changes, err := reconciler.Reconcile(ctx)
+	if err != nil {
+		t.Fatalf("Reconcile() dry run error = %v", err)
+	}
+	var actions []string
+	for _, change := range changes {
+		action := change.Env + "/" + change.Group + "/" + change.DataId + ":" + string(change.Action)
+		if change.Protected {
+			action += "!"
+		}
+		actions = append(actions, action)
+	}
+	want := []string{
+		"dev/DEFAULT_GROUP/app.yaml:unchanged",
+		"dev/DEFAULT_GROUP/new.yaml:create",
+		"dev/DEFAULT_GROUP/keep.yaml:delete!",
+		"dev/DEFAULT_GROUP/old.yaml:delete",
+		"prod/DEFAULT_GROUP/app.yaml:update",
+		"prod/DEFAULT_GROUP/db.yaml:update!",
+		"prod/SHARED/redis.properties:create",
+	}
+	if got := strings.Join(actions, " "); got != strings.Join(want, " ") {
+		t.Fatalf("Expected %v, got %v", want, actions)
+	}
+	if _, ok := dev.Get("old.yaml", ""); !ok {
+		t.Error("Expected dry run not to delete")
+	}
+	for _, line := range []string{"==> [delete] dev/DEFAULT_GROUP/old.yaml", "-stale: true", "(受保护，跳过)", "新增 2，更新 1，删除 1，未变化 1，受保护跳过 2\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+
+	reconciler.DryRun = false
+	if _, err := reconciler.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	check := func(client *nacostest.ConfigClient, dataId, group, want string) {
+		t.Helper()
+		content, ok := client.Get(dataId, group)
+		if want == "" && ok {
+			t.Errorf("Expected %s/%s to be deleted, got %q", group, dataId, content)
+		} else if want != "" && content != want {
+			t.Errorf("Expected %s/%s = %q, got %q", group, dataId, want, content)
+		}
+	}
+	check(dev, "new.yaml", "", "created: true\n")
+	check(dev, "old.yaml", "", "")
+	check(dev, "keep.yaml", "", "keep: true\n")
+	check(dev, "other.yaml", "OTHER", "untouched: true\n")
+	check(prod, "app.yaml", "", "port: 443\n")
+	check(prod, "db.yaml", "", "host: prod-db\n")
+	check(prod, "redis.properties", "SHARED", "addr=10.0.0.1\n")
+
+	// 不开启 Prune 时不删除
+	dev.Set("old.yaml", "DEFAULT_GROUP", "stale: true\n")
+	reconciler.Prune = false
+	changes, _ = reconciler.Plan(ctx)
+	for _, change := range changes {
+		if change.Action != ActionUnchanged && !change.Protected {
+			t.Errorf("Expected no pending changes without prune, got %s/%s %s", change.Env, change.DataId, change.Action)
+		}
+	}
+}
+
+func TestReconcilerErrors(t *testing.T) {
+	manager := &Manager{clients: map[string]*NacosClient{"dev": newTestClient(nacostest.NewConfigClient())}}
+	ctx := context.Background()
+
+	tests := map[string]map[string]string{
+		"unknown namespace": {"test/DEFAULT_GROUP/app.yaml": "a: 1"},
+		"too deep":          {"dev/DEFAULT_GROUP/sub/app.yaml": "a: 1"},
+		"missing group":     {"dev/app.yaml": "a: 1"},
+		"empty file":        {"dev/DEFAULT_GROUP/app.yaml": ""},
+	}
+	for name, files := range tests {
+		reconciler := &Reconciler{Manager: manager, Dir: writeTree(t, files)}
+		if _, err := reconciler.Plan(ctx); err == nil {
+			t.Errorf("%s: expected Plan to fail", name)
+		}
+	}
+
+	reconciler := &Reconciler{Manager: manager, Dir: t.TempDir(), Protected: []string{"["}}
+	if _, err := reconciler.Plan(ctx); err == nil {
+		t.Error("Expected invalid protected pattern to fail")
+	}
+
+	// 语法错误的文件不会发布，其他文件照常发布
+	dev := nacostest.NewConfigClient()
+	manager = &Manager{clients: map[string]*NacosClient{"dev": newTestClient(dev)}}
+	reconciler = &Reconciler{Manager: manager, Dir: writeTree(t, map[string]string{
+		"dev/DEFAULT_GROUP/bad.json":  "{",
+		"dev/DEFAULT_GROUP/good.yaml": "a: 1\n",
+	})}
+	if _, err := reconciler.Reconcile(ctx); ErrorCode(err) != ErrConfigInvalid.Code {
+		t.Errorf("Expected CONFIG_INVALID, got %v", err)
+	}
+	if content, _ := dev.Get("good.yaml", ""); content != "a: 1\n" {
+		t.Errorf("Expected good.yaml to be published, got %q", content)
+	}
+}
@@ -0,0 +1,225 @@
+package nacos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultGroup 未指定group时使用的分组
+const DefaultGroup = "DEFAULT_GROUP"
+
+// 配置源类型
+const (
+	SourceNacos  = "nacos"
+	SourceFile   = "file"
+	SourceEnv    = "env"
+	SourceMemory = "memory"
+)
+
+// ConfigSource 与后端无关的配置源
+//
+// 读取不存在的配置返回空字符串且无错误（与Nacos一致），
+// Watch 只推送之后的变化，配置被删除时推送空字符串
+type ConfigSource interface {
+	// Get 读取配置
+	Get(ctx context.Context, dataId, group string) (string, error)
+	// Publish 发布配置
+	Publish(ctx context.Context, dataId, group, content string) error
+	// Watch 监听配置变化，返回取消该监听的函数
+	Watch(ctx context.Context, dataId, group string, callback func(string)) (cancel func(), err error)
+	// Close 关闭配置源
+	Close() error
+}
+
+var (
+	_ ConfigSource = (*NacosClient)(nil)
+	_ ConfigSource = (*FileSource)(nil)
+	_ ConfigSource = (*EnvSource)(nil)
+	_ ConfigSource = (*MemorySource)(nil)
+)
+
+// SourceConfig 配置源配置
+type SourceConfig struct {
+	// Type 配置源类型：nacos（默认）、file、env、memory
+	Type string `mapstructure:"type"`
+	// Dir file 类型的配置目录，配置文件路径为 <dir>/<group>/<dataId>
+	Dir string `mapstructure:"dir"`
+	// Prefix env 类型的环境变量前缀
+	Prefix string `mapstructure:"prefix"`
+}
+
+// NewConfigSource 根据 config.Source.Type 创建配置源
+// opts 仅对 nacos 类型生效
+func NewConfigSource(config Config, opts ...Option) (ConfigSource, error) {
+	switch strings.ToLower(config.Source.Type) {
+	case "", SourceNacos:
+		return NewNacosClient(config, opts...)
+	case SourceFile:
+		return NewFileSource(config.Source.Dir)
+	case SourceEnv:
+		return NewEnvSource(config.Source.Prefix), nil
+	case SourceMemory:
+		return NewMemorySource(), nil
+	default:
+		return nil, NewNacosError(ErrConfigInvalid.Code, fmt.Sprintf("不支持的配置源类型: %s", config.Source.Type), nil)
+	}
+}
+
+// Get 读取配置，等同于 GetConfig
+func (c *NacosClient) Get(ctx context.Context, dataId, group string) (string, error) {
+	return c.GetConfig(ctx, dataId, group)
+}
+
+// Publish 发布配置，等同于 PublishConfig
+func (c *NacosClient) Publish(ctx context.Context, dataId, group, content string) error {
+	return c.PublishConfig(ctx, dataId, group, content)
+}
+
+// Watch 监听配置变化，返回取消该监听的函数
+func (c *NacosClient) Watch(ctx context.Context, dataId, group string, callback func(string)) (func(), error) {
+	var cb func(context.Context, string)
+	if callback != nil {
+		cb = func(_ context.Context, content string) {
+			callback(content)
+		}
+	}
+	return c.listen(ctx, dataId, group, cb)
+}
+
+// sourceKey 校验dataId并补全默认分组
+func sourceKey(dataId, group string) (string, string, error) {
+	if dataId == "" {
+		return "", "", NewNacosError(ErrConfigInvalid.Code, "dataId不能为空", nil)
+	}
+	if group == "" {
+		group = DefaultGroup
+	}
+	return dataId, group, nil
+}
+
+// listenCallback 单个监听回调
+type listenCallback struct {
+	id int
+	fn func(string)
+}
+
+// watchRegistry 按 dataId/group 维护监听回调，供非Nacos配置源使用
+type watchRegistry struct {
+	mu      sync.Mutex
+	nextID  int
+	entries map[string][]listenCallback
+}
+
+// add 注册回调，返回取消函数
+func (r *watchRegistry) add(dataId, group string, callback func(string)) func() {
+	key := group + "@@" + dataId
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = make(map[string][]listenCallback)
+	}
+	id := r.nextID
+	r.nextID++
+	r.entries[key] = append(r.entries[key], listenCallback{id: id, fn: callback})
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		callbacks := r.entries[key]
+		for i, cb := range callbacks {
+			if cb.id == id {
+				r.entries[key] = append(callbacks[:i:i], callbacks[i+1:]...)
+				break
+			}
+		}
+		if len(r.entries[key]) == 0 {
+			delete(r.entries, key)
+		}
+	}
+}
+
+// watching 返回 dataId/group 是否存在监听
+func (r *watchRegistry) watching(dataId, group string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries[group+"@@"+dataId]) > 0
+}
+
+// notify 依次调用 dataId/group 上的回调
+func (r *watchRegistry) notify(dataId, group, content string) {
+	r.mu.Lock()
+	var callbacks []func(string)
+	for _, cb := range r.entries[group+"@@"+dataId] {
+		callbacks = append(callbacks, cb.fn)
+	}
+	r.mu.Unlock()
+
+	for _, cb := range callbacks {
+		cb(content)
+	}
+}
+
+// MemorySource 基于内存的配置源，适用于本地开发与测试
+type MemorySource struct {
+	mu       sync.RWMutex
+	configs  map[string]string
+	watchers watchRegistry
+}
+
+// NewMemorySource 创建内存配置源
+func NewMemorySource() *MemorySource {
+	return &MemorySource{configs: make(map[string]string)}
+}
+
+// Get 读取配置
+func (s *MemorySource) Get(ctx context.Context, dataId, group string) (string, error) {
+	dataId, group, err := sourceKey(dataId, group)
+	if err != nil {
+		return "", err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.configs[group+"@@"+dataId], nil
+}
+
+// Publish 发布配置并通知监听者，content 为空时删除配置
+func (s *MemorySource) Publish(ctx context.Context, dataId, group, content string) error {
+	dataId, group, err := sourceKey(dataId, group)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	key := group + "@@" + dataId
+	old := s.configs[key]
+	if content == "" {
+		delete(s.configs, key)
+	} else {
+		s.configs[key] = content
+	}
+	s.mu.Unlock()
+
+	if old != content {
+		s.watchers.notify(dataId, group, content)
+	}
+	return nil
+}
+
+// Watch 监听配置变化
+func (s *MemorySource) Watch(ctx context.Context, dataId, group string, callback func(string)) (func(), error) {
+	dataId, group, err := sourceKey(dataId, group)
+	if err != nil {
+		return nil, err
+	}
+	if callback == nil {
+		return func() {}, nil
+	}
+	return s.watchers.add(dataId, group, callback), nil
+}
+
+// Close 关闭配置源
+func (s *MemorySource) Close() error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package nacos
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// EnvSource 基于环境变量的配置源
+//
+// dataId/group 对应的环境变量名为 前缀 + GROUP + "_" + DATAID，非字母数字字符替换为下划线并转为大写，
+// 例如前缀为 CONFIG_ 时 DEFAULT_GROUP/app.yaml 对应 CONFIG_DEFAULT_GROUP_APP_YAML。
+// 环境变量无法被外部修改，Watch 只能收到通过 Publish 发布的变化
+type EnvSource struct {
+	prefix   string
+	watchers watchRegistry
+}
+
+// NewEnvSource 创建环境变量配置源
+func NewEnvSource(prefix string) *EnvSource {
+	return &EnvSource{prefix: prefix}
+}
+
+// EnvName 返回 dataId/group 对应的环境变量名
+func (s *EnvSource) EnvName(dataId, group string) string {
+	if group == "" {
+		group = DefaultGroup
+	}
+	return s.prefix + envSegment(group) + "_" + envSegment(dataId)
+}
+
+// envSegment 将非字母数字字符替换为下划线并转为大写
+func envSegment(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// Get 读取配置
+func (s *EnvSource) Get(ctx context.Context, dataId, group string) (string, error) {
+	dataId, group, err := sourceKey(dataId, group)
+	if err != nil {
+		return "", err
+	}
+	return os.Getenv(s.EnvName(dataId, group)), nil
+}
+
+// Publish 设置当前进程的环境变量并通知监听者，content 为空时删除环境变量
+func (s *EnvSource) Publish(ctx context.Context, dataId, group, content string) error {
+	dataId, group, err := sourceKey(dataId, group)
+	if err != nil {
+		return err
+	}
+
+	name := s.EnvName(dataId, group)
+	old := os.Getenv(name)
+	if content == "" {
+		err = os.Unsetenv(name)
+	} else {
+		err = os.Setenv(name, content)
+	}
+	if err != nil {
+		return NewNacosError(ErrPublishFailed.Code, "设置环境变量失败: "+name, err)
+	}
+
+	if old != content {
+		s.watchers.notify(dataId, group, content)
+	}
+	return nil
+}
+
+// Watch 监听通过 Publish 发布的变化
+func (s *EnvSource) Watch(ctx context.Context, dataId, group string, callback func(string)) (func(), error) {
+	dataId, group, err := sourceKey(dataId, group)
+	if err != nil {
+		return nil, err
+	}
+	if callback == nil {
+		return func() {}, nil
+	}
+	return s.watchers.add(dataId, group, callback), nil
+}
+
+// Close 关闭配置源
+func (s *EnvSource) Close() error {
+	return nil
+}
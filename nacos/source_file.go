@@ -0,0 +1,222 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileSource 基于本地目录的配置源，配置文件路径为 <dir>/<group>/<dataId>
+//
+// 通过 fsnotify 监听分组目录，文件被修改、替换或删除后异步推送给监听者，
+// 内容未变化的事件会被忽略。适用于没有Nacos服务端的本地开发环境
+type FileSource struct {
+	dir     string
+	watcher *fsnotify.Watcher
+
+	mu          sync.Mutex
+	watchedDirs map[string]bool
+	last        map[string]string
+	watchers    watchRegistry
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewFileSource 创建目录配置源，目录不存在时自动创建
+func NewFileSource(dir string) (*FileSource, error) {
+	if dir == "" {
+		return nil, NewNacosError(ErrConfigInvalid.Code, "配置目录不能为空", nil)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, NewNacosError(ErrClientInitFailed.Code, fmt.Sprintf("创建配置目录失败: %s", dir), err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, NewNacosError(ErrClientInitFailed.Code, "创建文件监听失败", err)
+	}
+
+	s := &FileSource{
+		dir:         dir,
+		watcher:     watcher,
+		watchedDirs: make(map[string]bool),
+		last:        make(map[string]string),
+		done:        make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// path 返回 dataId/group 对应的文件路径，拒绝包含路径分隔符的名称
+func (s *FileSource) path(dataId, group string) (string, string, string, error) {
+	dataId, group, err := sourceKey(dataId, group)
+	if err != nil {
+		return "", "", "", err
+	}
+	for _, name := range []string{dataId, group} {
+		if name == "." || name == ".." || filepath.Base(name) != name {
+			return "", "", "", NewNacosError(ErrConfigInvalid.Code, fmt.Sprintf("无效的配置名称: %s", name), nil)
+		}
+	}
+	return dataId, group, filepath.Join(s.dir, group, dataId), nil
+}
+
+// readFile 读取配置文件，文件不存在时返回空内容
+func readFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Get 读取配置
+func (s *FileSource) Get(ctx context.Context, dataId, group string) (string, error) {
+	dataId, group, path, err := s.path(dataId, group)
+	if err != nil {
+		return "", err
+	}
+	content, err := readFile(path)
+	if err != nil {
+		return "", NewNacosError(ErrOperationFailed.Code, fmt.Sprintf("获取配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+	}
+	return content, nil
+}
+
+// Publish 写入配置文件（先写临时文件再重命名），content 为空时删除文件
+func (s *FileSource) Publish(ctx context.Context, dataId, group, content string) error {
+	dataId, group, path, err := s.path(dataId, group)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(path, content); err != nil {
+		return NewNacosError(ErrPublishFailed.Code, fmt.Sprintf("发布配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+	}
+	return nil
+}
+
+func writeFileAtomic(path, content string) error {
+	if content == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Watch 监听配置文件变化
+func (s *FileSource) Watch(ctx context.Context, dataId, group string, callback func(string)) (func(), error) {
+	dataId, group, path, err := s.path(dataId, group)
+	if err != nil {
+		return nil, err
+	}
+	if callback == nil {
+		return func() {}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Dir(path)
+	if !s.watchedDirs[dir] {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, NewNacosError(ErrListenFailed.Code, fmt.Sprintf("监听配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+		}
+		if err := s.watcher.Add(dir); err != nil {
+			return nil, NewNacosError(ErrListenFailed.Code, fmt.Sprintf("监听配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+		}
+		s.watchedDirs[dir] = true
+	}
+
+	if _, ok := s.last[path]; !ok {
+		content, err := readFile(path)
+		if err != nil {
+			return nil, NewNacosError(ErrListenFailed.Code, fmt.Sprintf("监听配置失败 [DataId: %s, Group: %s]", dataId, group), err)
+		}
+		s.last[path] = content
+	}
+	return s.watchers.add(dataId, group, callback), nil
+}
+
+// run 处理文件事件
+func (s *FileSource) run() {
+	defer close(s.done)
+	for {
+		select {
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			s.handle(event.Name)
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			packageLogger().Warn("配置文件监听出错", LogKeySource, "file", LogKeyError, err.Error())
+		}
+	}
+}
+
+// handle 文件变化后重新读取，内容变化时通知监听者
+func (s *FileSource) handle(path string) {
+	dataId := filepath.Base(path)
+	group := filepath.Base(filepath.Dir(path))
+	if !s.watchers.watching(dataId, group) {
+		return
+	}
+
+	content, err := readFile(path)
+	if err != nil {
+		packageLogger().Warn("读取配置文件失败", LogKeyGroup, group, LogKeyDataId, dataId, LogKeyError, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	changed := s.last[path] != content
+	s.last[path] = content
+	s.mu.Unlock()
+
+	if changed {
+		s.watchers.notify(dataId, group, content)
+	}
+}
+
+// Close 停止监听
+func (s *FileSource) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.watcher.Close()
+		<-s.done
+	})
+	return err
+}
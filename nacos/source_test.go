@@ -0,0 +1,157 @@
+package nacos
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+)
+
+func TestConfigSources(t *testing.T) {
+	fileSource, err := NewFileSource(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSource() error = %v", err)
+	}
+	defer fileSource.Close()
+
+	// 测试结束后恢复env配置源写入的环境变量
+	t.Setenv("NACOS_SOURCE_TEST_DEFAULT_GROUP_APP_YAML", "")
+
+	sdk := nacostest.NewConfigClient()
+	sources := map[string]ConfigSource{
+		"nacos":  newTestClient(sdk),
+		"file":   fileSource,
+		"env":    NewEnvSource("NACOS_SOURCE_TEST_"),
+		"memory": NewMemorySource(),
+	}
+
+	for name, source := range sources {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			pushes := make(chan string, 10)
+			cancel, err := source.Watch(ctx, "app.yaml", "DEFAULT_GROUP", func(content string) {
+				pushes <- content
+			})
+			if err != nil {
+				t.Fatalf("Watch() error = %v", err)
+			}
+
+			if err := source.Publish(ctx, "app.yaml", "DEFAULT_GROUP", "port: 8080"); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			if got := waitPush(t, pushes); got != "port: 8080" {
+				t.Errorf("Expected push %q, got %q", "port: 8080", got)
+			}
+
+			content, err := source.Get(ctx, "app.yaml", "DEFAULT_GROUP")
+			if err != nil || content != "port: 8080" {
+				t.Errorf("Get() = %q, %v", content, err)
+			}
+
+			cancel()
+			if err := source.Publish(ctx, "app.yaml", "DEFAULT_GROUP", "port: 9090"); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			select {
+			case got := <-pushes:
+				t.Errorf("Expected no push after cancel, got %q", got)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func waitPush(t *testing.T, pushes <-chan string) string {
+	t.Helper()
+	select {
+	case content := <-pushes:
+		return content
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for push")
+		return ""
+	}
+}
+
+func TestFileSourceExternalChanges(t *testing.T) {
+	dir := t.TempDir()
+	source, err := NewFileSource(dir)
+	if err != nil {
+		t.Fatalf("NewFileSource() error = %v", err)
+	}
+	defer source.Close()
+
+	ctx := context.Background()
+	if content, err := source.Get(ctx, "app.yaml", ""); err != nil || content != "" {
+		t.Fatalf("Get() of missing file = %q, %v", content, err)
+	}
+
+	pushes := make(chan string, 10)
+	if _, err := source.Watch(ctx, "app.yaml", "", func(content string) { pushes <- content }); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	path := filepath.Join(dir, DefaultGroup, "app.yaml")
+	if err := os.WriteFile(path, []byte("a: 1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := waitPush(t, pushes); got != "a: 1" {
+		t.Errorf("Expected push %q, got %q", "a: 1", got)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := waitPush(t, pushes); got != "" {
+		t.Errorf("Expected empty push after delete, got %q", got)
+	}
+
+	if _, err := source.Get(ctx, "../secret", ""); ErrorCode(err) != ErrConfigInvalid.Code {
+		t.Errorf("Expected %s for path traversal, got %v", ErrConfigInvalid.Code, err)
+	}
+}
+
+func TestNewConfigSource(t *testing.T) {
+	tests := []struct {
+		source  SourceConfig
+		want    string
+		wantErr bool
+	}{
+		{source: SourceConfig{Type: "memory"}, want: "*nacos.MemorySource"},
+		{source: SourceConfig{Type: "env", Prefix: "APP_"}, want: "*nacos.EnvSource"},
+		{source: SourceConfig{Type: "file", Dir: t.TempDir()}, want: "*nacos.FileSource"},
+		{source: SourceConfig{Type: "file"}, wantErr: true},
+		{source: SourceConfig{Type: "zookeeper"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source.Type, func(t *testing.T) {
+			source, err := NewConfigSource(Config{Source: tt.source})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewConfigSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer source.Close()
+			if got := fmt.Sprintf("%T", source); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvSourceName(t *testing.T) {
+	source := NewEnvSource("CONFIG_")
+	if got := source.EnvName("app.yaml", ""); got != "CONFIG_DEFAULT_GROUP_APP_YAML" {
+		t.Errorf("Expected CONFIG_DEFAULT_GROUP_APP_YAML, got %s", got)
+	}
+
+	t.Setenv("CONFIG_DEFAULT_GROUP_APP_YAML", "port: 1")
+	if content, _ := source.Get(context.Background(), "app.yaml", ""); content != "port: 1" {
+		t.Errorf("Expected content from environment, got %q", content)
+	}
+}
@@ -0,0 +1,206 @@
+package nacos
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// configTypeTOML 控制台没有toml类型，toml配置以text发布，只用于语法检查
+const configTypeTOML = "toml"
+
+// SyntaxError 配置内容的语法错误
+// Line、Column 从1开始，解析器未提供时为0；yaml解析器只提供行号，
+// 对未闭合的结构（如缺少 ] 的数组）给出的行号可能在实际出错位置之前
+type SyntaxError struct {
+	DataId string
+	Type   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	position := ""
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		position = fmt.Sprintf("第%d行第%d列: ", e.Line, e.Column)
+	case e.Line > 0:
+		position = fmt.Sprintf("第%d行: ", e.Line)
+	}
+	return fmt.Sprintf("配置 %s 不是合法的%s: %s%s", e.DataId, e.Type, position, e.Msg)
+}
+
+// syntaxType 返回发布前需要做语法检查的类型，不需要检查时返回空字符串
+// configType 为 publishType 的结果，explicit 表示是否由调用方指定
+func syntaxType(dataId, configType string, explicit bool) string {
+	switch configType {
+	case ConfigTypeYAML, ConfigTypeJSON, ConfigTypeXML, ConfigTypeProperties:
+		return configType
+	case ConfigTypeText:
+		if !explicit && strings.EqualFold(filepath.Ext(dataId), ".toml") {
+			return configTypeTOML
+		}
+	}
+	return ""
+}
+
+// checkSyntax 按类型解析配置内容，语法错误时返回 CONFIG_INVALID，错误链中包含 *SyntaxError
+func checkSyntax(dataId, group, configType, content string) error {
+	var err *SyntaxError
+	switch configType {
+	case ConfigTypeYAML:
+		err = checkYAML(content)
+	case ConfigTypeJSON:
+		err = checkJSON(content)
+	case ConfigTypeXML:
+		err = checkXML(content)
+	case ConfigTypeProperties:
+		err = checkProperties(content)
+	case configTypeTOML:
+		err = checkTOML(content)
+	}
+	if err == nil {
+		return nil
+	}
+
+	err.DataId, err.Type = dataId, configType
+	return NewNacosError(ErrConfigInvalid.Code,
+		fmt.Sprintf("配置语法错误 [DataId: %s, Group: %s]", dataId, group), err)
+}
+
+// yamlLine 匹配yaml错误信息中的行号，如 "yaml: line 3: did not find expected key"
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+func checkYAML(content string) *SyntaxError {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		// 解码为通用结构才能发现重复的键
+		var doc interface{}
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil {
+			continue
+		}
+
+		msg := err.Error()
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+			msg = typeErr.Errors[0]
+		}
+		if m := yamlLine.FindStringSubmatch(strings.TrimSpace(msg)); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return &SyntaxError{Line: line, Msg: m[2]}
+		}
+		return &SyntaxError{Msg: strings.TrimPrefix(msg, "yaml: ")}
+	}
+}
+
+func checkJSON(content string) *SyntaxError {
+	var doc interface{}
+	err := json.Unmarshal([]byte(content), &doc)
+	if err == nil {
+		return nil
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset 为读取到出错字符之后的偏移
+		line, column := position(content, syntaxErr.Offset-1)
+		return &SyntaxError{Line: line, Column: column, Msg: syntaxErr.Error()}
+	}
+	return &SyntaxError{Msg: err.Error()}
+}
+
+func checkXML(content string) *SyntaxError {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	// 只检查语法，不处理字符集声明
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	roots, depth := 0, 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line, column := position(content, decoder.InputOffset())
+			msg := err.Error()
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				msg = syntaxErr.Msg
+			}
+			return &SyntaxError{Line: line, Column: column, Msg: msg}
+		}
+
+		switch token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+		if roots > 1 {
+			line, column := position(content, decoder.InputOffset())
+			return &SyntaxError{Line: line, Column: column, Msg: "只能有一个根元素"}
+		}
+	}
+	if roots == 0 {
+		return &SyntaxError{Msg: "缺少根元素"}
+	}
+	return nil
+}
+
+func checkProperties(content string) *SyntaxError {
+	_, err := parseProperties(content)
+	if err == nil {
+		return nil
+	}
+	var propsErr *propertiesError
+	if errors.As(err, &propsErr) {
+		return &SyntaxError{Line: propsErr.Line, Column: propsErr.Column, Msg: propsErr.Err.Error()}
+	}
+	return &SyntaxError{Msg: err.Error()}
+}
+
+func checkTOML(content string) *SyntaxError {
+	var doc map[string]interface{}
+	err := toml.Unmarshal([]byte(content), &doc)
+	if err == nil {
+		return nil
+	}
+	var decodeErr *toml.DecodeError
+	if errors.As(err, &decodeErr) {
+		line, column := decodeErr.Position()
+		return &SyntaxError{Line: line, Column: column, Msg: strings.TrimPrefix(decodeErr.Error(), "toml: ")}
+	}
+	return &SyntaxError{Msg: err.Error()}
+}
+
+// position 将从0开始的字节偏移转换为从1开始的行号和列号，列号按字符计算
+func position(content string, offset int64) (line, column int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	before := content[:offset]
+	line = strings.Count(before, "\n") + 1
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	column = utf8.RuneCountInString(before[lineStart:]) + 1
+	return line, column
+}
@@ -0,0 +1,105 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+)
+
+func TestCheckSyntax(t *testing.T) {
+	tests := []struct {
+		name       string
+		configType string
+		content    string
+		line       int
+		column     int
+	}{
+		{"yaml valid", ConfigTypeYAML, "a: 1\n---\nb: [1, 2]\n", 0, 0},
+		{"yaml tab", ConfigTypeYAML, "a: 1\n\tb: 2\n", 2, 0},
+		{"yaml duplicate key", ConfigTypeYAML, "a: 1\na: 2\n", 2, 0},
+		{"yaml second document", ConfigTypeYAML, "a: 1\n---\nb: [1\n", -1, 0},
+		{"json valid", ConfigTypeJSON, `{"a": [1, 2]}`, 0, 0},
+		{"json trailing comma", ConfigTypeJSON, "{\n  \"a\": 1,\n}", 3, 1},
+		{"json multibyte column", ConfigTypeJSON, "{\"名称\": x}", 1, 8},
+		{"json truncated", ConfigTypeJSON, "{\"a\": ", 1, 6},
+		{"xml valid", ConfigTypeXML, "<?xml version=\"1.0\" encoding=\"GBK\"?>\n<a><b>1</b></a>", 0, 0},
+		{"xml mismatched tag", ConfigTypeXML, "<a>\n  <b>1</c>\n</a>", 2, 0},
+		{"xml two roots", ConfigTypeXML, "<a/>\n<b/>", 2, 0},
+		{"properties valid", ConfigTypeProperties, "a=1\nb : 2\nc \\\n  3\n", 0, 0},
+		{"properties empty key", ConfigTypeProperties, "a=1\n  =2\n", 2, 3},
+		{"toml valid", configTypeTOML, "a = 1\n[b]\nc = \"x\"\n", 0, 0},
+		{"toml invalid", configTypeTOML, "a = 1\nb = \n", 2, 0},
+		{"text", ConfigTypeText, "{{ not checked", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSyntax("app", "DEFAULT_GROUP", tt.configType, tt.content)
+			if tt.line == 0 {
+				if err != nil {
+					t.Fatalf("checkSyntax() error = %v", err)
+				}
+				return
+			}
+
+			if ErrorCode(err) != ErrConfigInvalid.Code {
+				t.Fatalf("Expected CONFIG_INVALID, got %v", err)
+			}
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected *SyntaxError in chain, got %v", err)
+			}
+			// -1 表示只要求给出行号：yaml解析器对未闭合的结构给出的行号可能在出错位置之前
+			if (tt.line > 0 && syntaxErr.Line != tt.line) || syntaxErr.Line <= 0 || (tt.column > 0 && syntaxErr.Column != tt.column) {
+				t.Errorf("Expected position %d:%d, got %d:%d (%v)", tt.line, tt.column, syntaxErr.Line, syntaxErr.Column, err)
+			}
+			if syntaxErr.Type != tt.configType || syntaxErr.DataId != "app" || syntaxErr.Msg == "" {
+				t.Errorf("Unexpected syntax error: %+v", syntaxErr)
+			}
+		})
+	}
+}
+
+func TestSyntaxType(t *testing.T) {
+	tests := []struct {
+		dataId, configType string
+		explicit           bool
+		want               string
+	}{
+		{"app.yaml", ConfigTypeYAML, false, ConfigTypeYAML},
+		{"app.toml", ConfigTypeText, false, configTypeTOML},
+		{"app.toml", ConfigTypeText, true, ""},
+		{"app.yaml", ConfigTypeText, true, ""},
+		{"index.html", ConfigTypeHTML, false, ""},
+	}
+	for _, tt := range tests {
+		if got := syntaxType(tt.dataId, tt.configType, tt.explicit); got != tt.want {
+			t.Errorf("syntaxType(%q, %q, %v) = %q, want %q", tt.dataId, tt.configType, tt.explicit, got, tt.want)
+		}
+	}
+}
+
+func TestPublishRejectsSyntaxErrors(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	client := newTestClient(sdk)
+	ctx := context.Background()
+
+	err := client.PublishConfig(ctx, "app.yaml", "", "server:\n\tport: 8080\n")
+	var syntaxErr *SyntaxError
+	if ErrorCode(err) != ErrConfigInvalid.Code || !errors.As(err, &syntaxErr) || syntaxErr.Line != 2 {
+		t.Fatalf("Expected CONFIG_INVALID at line 2, got %v", err)
+	}
+	if _, ok := sdk.Get("app.yaml", "DEFAULT_GROUP"); ok {
+		t.Error("Expected broken yaml not to be published")
+	}
+
+	// 显式指定 text 时不做语法检查
+	if err := client.PublishConfigWithOptions(ctx, "app.yaml", "", "\tnot: yaml", PublishOptions{Type: ConfigTypeText}); err != nil {
+		t.Errorf("PublishConfigWithOptions() with text type error = %v", err)
+	}
+	if err := client.PublishConfig(ctx, "db.json", "", `{"port": 3306}`); err != nil {
+		t.Errorf("PublishConfig() valid json error = %v", err)
+	}
+}
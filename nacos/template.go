@@ -0,0 +1,280 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// TemplateExt 模板文件扩展名，LoadTemplates 去掉该扩展名后作为dataId
+const TemplateExt = ".tmpl"
+
+// ChangeAction 配置与线上内容比较后的动作
+type ChangeAction string
+
+// 比较结果
+const (
+	ActionCreate    ChangeAction = "create"
+	ActionUpdate    ChangeAction = "update"
+	ActionUnchanged ChangeAction = "unchanged"
+)
+
+// ConfigChange 单个配置的期望内容与线上内容的差异
+type ConfigChange struct {
+	// Env 环境名
+	Env     string
+	DataId  string
+	Group   string
+	Action  ChangeAction
+	Current string
+	Desired string
+	// Diff 线上内容到期望内容的统一格式diff，未变化时为空
+	Diff string
+	// Protected 受保护的配置只比较，不会被修改（见 Reconciler.Protected）
+	Protected bool
+
+	client  *NacosClient
+	options PublishOptions
+}
+
+// ConfigTemplate 一个dataId的配置模板
+type ConfigTemplate struct {
+	DataId string
+	// Group 为空时使用各环境客户端的默认group
+	Group string
+	// Text text/template 模板内容
+	Text string
+	// Options 发布时附带的元数据
+	Options PublishOptions
+}
+
+// Environment 发布模板的目标环境，通常一个环境对应一个命名空间
+type Environment struct {
+	Name   string
+	Client *NacosClient
+	// Values 渲染模板使用的值，模板中通过 .Values 访问
+	Values map[string]interface{}
+}
+
+// TemplateData 渲染模板时的数据
+type TemplateData struct {
+	// Env 环境名
+	Env string
+	// Namespace 环境客户端的命名空间
+	Namespace string
+	Values    map[string]interface{}
+}
+
+// TemplatePublisher 将模板按环境渲染后发布到对应命名空间，只发布内容有变化的dataId
+type TemplatePublisher struct {
+	Templates    []ConfigTemplate
+	Environments []Environment
+	// Output 不为nil时写入每个有变化的配置的diff
+	Output io.Writer
+	// DryRun 只比较并输出diff，不发布
+	DryRun bool
+}
+
+// LoadTemplates 读取目录下的 *.tmpl 文件作为模板，dataId 为去掉 .tmpl 后的文件名
+func LoadTemplates(dir, group string) ([]ConfigTemplate, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+TemplateExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	templates := make([]ConfigTemplate, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取模板失败 [%s]: %w", path, err)
+		}
+		templates = append(templates, ConfigTemplate{
+			DataId: strings.TrimSuffix(filepath.Base(path), TemplateExt),
+			Group:  group,
+			Text:   string(data),
+		})
+	}
+	return templates, nil
+}
+
+// LoadValues 读取环境的values文件，按扩展名解析 yaml/json/toml/properties，默认按yaml解析
+func LoadValues(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取values文件失败: %w", err)
+	}
+	doc, err := decodeContent(path, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("解析values文件失败 [%s]: %w", path, err)
+	}
+	if doc == nil {
+		return map[string]interface{}{}, nil
+	}
+	values, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("values文件 %s 的顶层必须是对象", path)
+	}
+	return values, nil
+}
+
+// Environments 按客户端名称为每个values创建环境，名称不存在时返回 CLIENT_NOT_FOUND
+func (m *Manager) Environments(values map[string]map[string]interface{}) ([]Environment, error) {
+	envs := make([]Environment, 0, len(values))
+	for _, name := range sortedNames(values) {
+		client, err := m.Client(name)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, Environment{Name: name, Client: client, Values: values[name]})
+	}
+	return envs, nil
+}
+
+// Plan 渲染全部模板并与各环境的线上内容比较，任一模板渲染失败时返回错误
+func (p *TemplatePublisher) Plan(ctx context.Context) ([]ConfigChange, error) {
+	parsed := make([]*template.Template, len(p.Templates))
+	for i, tmpl := range p.Templates {
+		t, err := template.New(tmpl.DataId).Option("missingkey=error").Funcs(templateFuncs).Parse(tmpl.Text)
+		if err != nil {
+			return nil, NewNacosError(ErrConfigInvalid.Code, fmt.Sprintf("解析模板失败 [DataId: %s]", tmpl.DataId), err)
+		}
+		parsed[i] = t
+	}
+
+	var changes []ConfigChange
+	for _, env := range p.Environments {
+		if env.Client == nil {
+			return nil, NewNacosError(ErrClientNotInit.Code, fmt.Sprintf("环境 %s 没有客户端", env.Name), nil)
+		}
+		data := TemplateData{Env: env.Name, Namespace: env.Client.config.Nacos.Namespace, Values: env.Values}
+
+		for i, tmpl := range p.Templates {
+			var sb strings.Builder
+			if err := parsed[i].Execute(&sb, data); err != nil {
+				return nil, NewNacosError(ErrConfigInvalid.Code,
+					fmt.Sprintf("渲染模板失败 [Env: %s, DataId: %s]", env.Name, tmpl.DataId), err)
+			}
+
+			group := tmpl.Group
+			if group == "" {
+				group = env.Client.config.Nacos.Group
+			}
+			current, err := env.Client.liveContent(ctx, tmpl.DataId, group)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, newConfigChange(env.Name, tmpl.DataId, group, current, sb.String(), env.Client, tmpl.Options))
+		}
+	}
+	return changes, nil
+}
+
+// Publish 比较并发布有变化的配置，DryRun 时只比较
+// 所有模板渲染成功后才开始发布，单个配置发布失败不影响其他配置，错误合并后返回
+func (p *TemplatePublisher) Publish(ctx context.Context) ([]ConfigChange, error) {
+	changes, err := p.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if p.Output != nil {
+		writeChanges(p.Output, changes)
+	}
+	if p.DryRun {
+		return changes, nil
+	}
+
+	var errs []error
+	for _, change := range changes {
+		if change.Action == ActionUnchanged {
+			continue
+		}
+		if err := change.client.PublishConfigWithOptions(ctx, change.DataId, change.Group, change.Desired, change.options); err != nil {
+			errs = append(errs, fmt.Errorf("环境 %s: %w", change.Env, err))
+		}
+	}
+	return changes, errors.Join(errs...)
+}
+
+// newConfigChange 比较线上内容与期望内容，线上内容为空视为不存在
+func newConfigChange(env, dataId, group, current, desired string, client *NacosClient, options PublishOptions) ConfigChange {
+	change := ConfigChange{
+		Env:     env,
+		DataId:  dataId,
+		Group:   group,
+		Current: current,
+		Desired: desired,
+		client:  client,
+		options: options,
+	}
+	switch {
+	case current == desired:
+		change.Action = ActionUnchanged
+	case current == "":
+		change.Action = ActionCreate
+	default:
+		change.Action = ActionUpdate
+	}
+	if change.Action != ActionUnchanged {
+		name := env + "/" + group + "/" + dataId
+		change.Diff = unifiedDiff(name+" (线上)", name, current, desired)
+	}
+	return change
+}
+
+// writeChanges 输出有变化的配置的diff和汇总
+func writeChanges(w io.Writer, changes []ConfigChange) {
+	counts := make(map[ChangeAction]int)
+	skipped := 0
+	for _, change := range changes {
+		if change.Protected && change.Action != ActionUnchanged {
+			skipped++
+		} else {
+			counts[change.Action]++
+		}
+		if change.Diff == "" {
+			continue
+		}
+		note := ""
+		if change.Protected {
+			note = " (受保护，跳过)"
+		}
+		fmt.Fprintf(w, "==> [%s] %s/%s/%s%s\n%s\n", change.Action, change.Env, change.Group, change.DataId, note, change.Diff)
+	}
+	fmt.Fprintf(w, "新增 %d，更新 %d，删除 %d，未变化 %d",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete], counts[ActionUnchanged])
+	if skipped > 0 {
+		fmt.Fprintf(w, "，受保护跳过 %d", skipped)
+	}
+	fmt.Fprintln(w)
+}
+
+// liveContent 读取线上的配置内容（解密后、未解析占位符），用于与期望内容比较
+func (c *NacosClient) liveContent(ctx context.Context, dataId, group string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fetchConfig(ctx, dataId, group)
+}
+
+// templateFuncs 模板中可用的函数
+// 模板以 missingkey=error 执行，引用values中不存在的键会渲染失败
+var templateFuncs = template.FuncMap{
+	// required 值为null或空字符串时渲染失败：{{ required "db.host不能为空" .Values.db.host }}
+	"required": func(msg string, value interface{}) (interface{}, error) {
+		if value == nil || value == "" {
+			return nil, errors.New(msg)
+		}
+		return value, nil
+	},
+	// quote 输出带引号的字符串
+	"quote": func(value interface{}) string {
+		return strconv.Quote(fmt.Sprint(value))
+	},
+}
@@ -0,0 +1,162 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+)
+
+func newTemplateEnv(name string, values map[string]interface{}) (Environment, *nacostest.ConfigClient) {
+	fake := nacostest.NewConfigClient()
+	client := newTestClient(fake)
+	client.config.Nacos.Namespace = name
+	return Environment{Name: name, Client: client, Values: values}, fake
+}
+
+func TestTemplatePublisher(t *testing.T) {
+	dev, devSDK := newTemplateEnv("dev", map[string]interface{}{"port": 8080, "db": map[string]interface{}{"host": "dev-db"}})
+	prod, prodSDK := newTemplateEnv("prod", map[string]interface{}{"port": 80, "db": map[string]interface{}{"host": "prod-db"}})
+	devSDK.Set("app.yaml", "", "env: dev\nport: 8080\n")
+	devSDK.Set("db.yaml", "", "host: \"dev-db\"\n")
+	prodSDK.Set("app.yaml", "", "env: prod\nport: 8080\n")
+
+	var out strings.Builder
+	publisher := &TemplatePublisher{
+		Templates: []ConfigTemplate{
+			{DataId: "app.yaml", Text: "env: {{ .Env }}\nport: {{ .Values.port }}\n"},
+			{DataId: "db.yaml", Text: "host: {{ quote .Values.db.host }}\n", Options: PublishOptions{AppName: "order"}},
+		},
+		Environments: []Environment{dev, prod},
+		Output:       &out,
+		DryRun:       true,
+	}
+	ctx := context.Background()
+
+	changes, err := publisher.Publish(ctx)
+	if err != nil {
+		t.Fatalf("Publish() dry run error = %v", err)
+	}
+	var actions []string
+	for _, change := range changes {
+		actions = append(actions, change.Env+"/"+change.DataId+":"+string(change.Action))
+	}
+	want := "dev/app.yaml:unchanged dev/db.yaml:unchanged prod/app.yaml:update prod/db.yaml:create"
+	if got := strings.Join(actions, " "); got != want {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+	if content, _ := prodSDK.Get("app.yaml", ""); content != "env: prod\nport: 8080\n" {
+		t.Errorf("Expected dry run not to publish, got %q", content)
+	}
+	for _, line := range []string{"==> [update] prod/DEFAULT_GROUP/app.yaml", "-port: 8080", "+port: 80", "==> [create] prod/DEFAULT_GROUP/db.yaml", "新增 1，更新 1，删除 0，未变化 2\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "dev/") {
+		t.Errorf("Expected unchanged configs to be omitted from output, got:\n%s", out.String())
+	}
+
+	// 未变化的环境不会发布
+	devSDK.Fail(nacostest.OpPublish, errors.New("unexpected publish"))
+	publisher.DryRun, publisher.Output = false, nil
+	if _, err := publisher.Publish(ctx); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if content, _ := prodSDK.Get("db.yaml", ""); content != "host: \"prod-db\"\n" {
+		t.Errorf("Expected prod db.yaml to be published, got %q", content)
+	}
+	if metadata, _ := prod.Client.GetConfigMetadata(ctx, "db.yaml", ""); metadata == nil || metadata.AppName != "order" {
+		t.Errorf("Expected publish options to be applied, got %+v", metadata)
+	}
+
+	changes, _ = publisher.Plan(ctx)
+	for _, change := range changes {
+		if change.Action != ActionUnchanged {
+			t.Errorf("Expected everything unchanged after publish, got %s/%s %s", change.Env, change.DataId, change.Action)
+		}
+	}
+}
+
+func TestTemplatePublisherRenderError(t *testing.T) {
+	dev, _ := newTemplateEnv("dev", map[string]interface{}{"port": 8080})
+	prod, prodSDK := newTemplateEnv("prod", map[string]interface{}{})
+	publisher := &TemplatePublisher{
+		Templates:    []ConfigTemplate{{DataId: "app.yaml", Text: "port: {{ .Values.port }}\n"}},
+		Environments: []Environment{dev, prod},
+	}
+
+	if _, err := publisher.Publish(context.Background()); ErrorCode(err) != ErrConfigInvalid.Code || !strings.Contains(err.Error(), "prod") {
+		t.Fatalf("Expected CONFIG_INVALID for missing value in prod, got %v", err)
+	}
+	if _, ok := dev.Client.client.(*nacostest.ConfigClient).Get("app.yaml", ""); ok {
+		t.Error("Expected nothing to be published when any environment fails to render")
+	}
+	if _, ok := prodSDK.Get("app.yaml", ""); ok {
+		t.Error("Expected nothing to be published to prod")
+	}
+
+	publisher.Templates[0].Text = "port: {{ required \"port不能为空\" .Values.port }}\n"
+	publisher.Environments[1].Values = map[string]interface{}{"port": ""}
+	if _, err := publisher.Plan(context.Background()); err == nil || !strings.Contains(err.Error(), "port不能为空") {
+		t.Errorf("Expected required to fail, got %v", err)
+	}
+}
+
+func TestLoadTemplatesAndValues(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml.tmpl": "port: {{ .Values.port }}\n",
+		"db.json.tmpl":  "{}",
+		"README.md":     "ignored",
+		"dev.yaml":      "port: 8080\ndb:\n  host: dev-db\n",
+		"prod.json":     `{"port": 80}`,
+		"list.yaml":     "- a\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := LoadTemplates(dir, "SHARED")
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+	if len(templates) != 2 || templates[0].DataId != "app.yaml" || templates[1].DataId != "db.json" || templates[0].Group != "SHARED" {
+		t.Errorf("Unexpected templates: %+v", templates)
+	}
+
+	values, err := LoadValues(filepath.Join(dir, "dev.yaml"))
+	if err != nil || values["port"] != 8080 || values["db"].(map[string]interface{})["host"] != "dev-db" {
+		t.Errorf("LoadValues(dev.yaml) = %v, %v", values, err)
+	}
+	if values, err := LoadValues(filepath.Join(dir, "prod.json")); err != nil || values["port"] == nil {
+		t.Errorf("LoadValues(prod.json) = %v, %v", values, err)
+	}
+	if _, err := LoadValues(filepath.Join(dir, "list.yaml")); err == nil {
+		t.Error("Expected non-object values file to fail")
+	}
+}
+
+func TestManagerEnvironments(t *testing.T) {
+	manager := &Manager{clients: map[string]*NacosClient{
+		"dev":  newTestClient(nacostest.NewConfigClient()),
+		"prod": newTestClient(nacostest.NewConfigClient()),
+	}}
+
+	envs, err := manager.Environments(map[string]map[string]interface{}{"prod": {"port": 80}, "dev": {"port": 8080}})
+	if err != nil {
+		t.Fatalf("Environments() error = %v", err)
+	}
+	if len(envs) != 2 || envs[0].Name != "dev" || envs[1].Name != "prod" || envs[1].Values["port"] != 80 {
+		t.Errorf("Unexpected environments: %+v", envs)
+	}
+	if _, err := manager.Environments(map[string]map[string]interface{}{"test": nil}); ErrorCode(err) != ErrClientNotFound.Code {
+		t.Errorf("Expected %s, got %v", ErrClientNotFound.Code, err)
+	}
+}
@@ -0,0 +1,77 @@
+package nacos
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName OpenTelemetry instrumentation 名称
+const tracerName = "github.com/fuyx123/common-package/nacos"
+
+// span 属性名
+const (
+	attrNamespace   = attribute.Key("nacos.namespace")
+	attrGroup       = attribute.Key("nacos.group")
+	attrDataId      = attribute.Key("nacos.data_id")
+	attrContentSize = attribute.Key("nacos.content_size")
+	attrErrorCode   = attribute.Key("nacos.error_code")
+)
+
+// WithTracerProvider 设置创建span使用的TracerProvider，未设置时使用otel全局TracerProvider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *NacosClient) {
+		c.tracerProvider = provider
+	}
+}
+
+// tracer 返回客户端使用的Tracer
+func (c *NacosClient) tracer() trace.Tracer {
+	provider := c.tracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
+
+// startSpan 为一次客户端调用创建span
+func (c *NacosClient) startSpan(ctx context.Context, operation, dataId, group string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opts = append(opts, trace.WithAttributes(
+		attrNamespace.String(c.config.Nacos.Namespace),
+		attrGroup.String(group),
+		attrDataId.String(dataId),
+	))
+	return c.tracer().Start(ctx, "nacos."+operation, opts...)
+}
+
+// endSpan 记录调用结果并结束span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if code := ErrorCode(err); code != "" {
+			span.SetAttributes(attrErrorCode.String(code))
+		}
+	}
+	span.End()
+}
+
+// tracedCallback 包装监听回调，每次推送都创建一个关联到 ListenConfig 调用span的新span
+func (c *NacosClient) tracedCallback(listenSpan trace.SpanContext, dataId, group string, callback func(context.Context, string)) func(string) {
+	return func(content string) {
+		ctx, span := c.startSpan(context.Background(), "OnChange", dataId, group,
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithLinks(trace.Link{SpanContext: listenSpan}),
+		)
+		defer span.End()
+
+		span.SetAttributes(attrContentSize.Int(len(content)))
+		callback(ctx, content)
+	}
+}
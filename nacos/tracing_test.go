@@ -0,0 +1,78 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestClientTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("app.yaml", "DEFAULT_GROUP", "a: 1")
+	client := newTestClient(sdk, WithTracerProvider(provider))
+	ctx := context.Background()
+
+	if _, err := client.GetConfig(ctx, "app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	sdk.FailTimes(nacostest.OpGet, errors.New("server error"), 1)
+	if _, err := client.GetConfig(ctx, "app.yaml", "DEFAULT_GROUP"); err == nil {
+		t.Fatal("Expected GetConfig() to fail")
+	}
+
+	var callbackSpan trace.SpanContext
+	if err := client.ListenConfigContext(ctx, "app.yaml", "DEFAULT_GROUP", func(ctx context.Context, content string) {
+		callbackSpan = trace.SpanContextFromContext(ctx)
+	}); err != nil {
+		t.Fatalf("ListenConfigContext() error = %v", err)
+	}
+	sdk.PublishConfig(vo.ConfigParam{DataId: "app.yaml", Group: "DEFAULT_GROUP", Content: "a: 2"})
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("Expected 4 spans, got %d", len(spans))
+	}
+
+	get := spans[0]
+	if get.Name() != "nacos.GetConfig" {
+		t.Errorf("Expected span nacos.GetConfig, got %s", get.Name())
+	}
+	attrs := map[string]string{}
+	for _, kv := range get.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["nacos.data_id"] != "app.yaml" || attrs["nacos.group"] != "DEFAULT_GROUP" || attrs["nacos.content_size"] != "4" {
+		t.Errorf("Unexpected attributes: %v", attrs)
+	}
+
+	failed := spans[1]
+	found := false
+	for _, kv := range failed.Attributes() {
+		if kv.Key == attrErrorCode && kv.Value.AsString() == ErrOperationFailed.Code {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected error code attribute on failed span, got %v", failed.Attributes())
+	}
+
+	listen, onChange := spans[2], spans[3]
+	if onChange.Name() != "nacos.OnChange" {
+		t.Fatalf("Expected span nacos.OnChange, got %s", onChange.Name())
+	}
+	if len(onChange.Links()) != 1 || onChange.Links()[0].SpanContext.SpanID() != listen.SpanContext().SpanID() {
+		t.Error("Expected OnChange span to link to ListenConfig span")
+	}
+	if callbackSpan.SpanID() != onChange.SpanContext().SpanID() {
+		t.Error("Expected callback context to carry the OnChange span")
+	}
+}
@@ -0,0 +1,247 @@
+package nacos
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Validator 配置内容校验器
+// 校验失败时返回 *ValidationError 可以携带多个违规路径，返回其他错误视为整体校验失败
+type Validator interface {
+	Validate(dataId, content string) error
+}
+
+// ValidatorFunc 使用函数实现的校验器
+type ValidatorFunc func(dataId, content string) error
+
+// Validate 调用校验函数
+func (f ValidatorFunc) Validate(dataId, content string) error {
+	return f(dataId, content)
+}
+
+// Violation 单条校验违规
+type Violation struct {
+	Path    string
+	Message string
+}
+
+// ValidationError 配置校验失败的详细信息
+type ValidationError struct {
+	DataId     string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s: %s", v.Path, v.Message))
+	}
+	return fmt.Sprintf("配置 %s 校验未通过: %s", e.DataId, strings.Join(parts, "; "))
+}
+
+// Paths 返回全部违规路径
+func (e *ValidationError) Paths() []string {
+	paths := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		paths = append(paths, v.Path)
+	}
+	return paths
+}
+
+// validatorRegistry 按 dataId/group 注册的校验器
+type validatorRegistry struct {
+	mu         sync.RWMutex
+	validators map[string]Validator
+}
+
+// RegisterValidator 为指定 dataId/group 注册校验器
+// 注册后 PublishConfig 会拒绝不符合要求的内容，GetConfig 返回错误，
+// 未通过校验的推送会被丢弃并记录日志，不会交给监听回调
+func (c *NacosClient) RegisterValidator(dataId, group string, validator Validator) {
+	if group == "" {
+		group = c.config.Nacos.Group
+	}
+
+	c.validators.mu.Lock()
+	defer c.validators.mu.Unlock()
+	if c.validators.validators == nil {
+		c.validators.validators = make(map[string]Validator)
+	}
+	if validator == nil {
+		delete(c.validators.validators, group+"@@"+dataId)
+		return
+	}
+	c.validators.validators[group+"@@"+dataId] = validator
+}
+
+// validateContent 使用注册的校验器校验配置内容
+func (c *NacosClient) validateContent(dataId, group, content string) error {
+	c.validators.mu.RLock()
+	validator := c.validators.validators[group+"@@"+dataId]
+	c.validators.mu.RUnlock()
+	if validator == nil {
+		return nil
+	}
+
+	err := validator.Validate(dataId, content)
+	if err == nil {
+		return nil
+	}
+
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		validationErr = &ValidationError{
+			DataId:     dataId,
+			Violations: []Violation{{Path: "/", Message: err.Error()}},
+		}
+	}
+	if validationErr.DataId == "" {
+		validationErr.DataId = dataId
+	}
+	return NewNacosError(ErrConfigInvalid.Code,
+		fmt.Sprintf("配置校验失败 [DataId: %s, Group: %s]", dataId, group), validationErr)
+}
+
+// validatingCallback 包装监听回调，未通过校验的推送不会交给回调，返回校验失败的原因
+func (c *NacosClient) validatingCallback(dataId, group string, callback func(string)) func(string) error {
+	return func(content string) error {
+		if err := c.validateContent(dataId, group, content); err != nil {
+			c.clientLogger().Warn("拒绝未通过校验的配置推送", c.logFields(dataId, group, err)...)
+			return err
+		}
+		if callback != nil {
+			callback(content)
+		}
+		return nil
+	}
+}
+
+// JSONSchemaValidator 基于JSON Schema的校验器
+// 配置内容按dataId扩展名解析(yaml/json/properties/toml)后再校验
+type JSONSchemaValidator struct {
+	schema *jsonschema.Schema
+}
+
+var _ Validator = (*JSONSchemaValidator)(nil)
+
+// NewJSONSchemaValidator 根据JSON Schema文本创建校验器
+func NewJSONSchemaValidator(schema string) (*JSONSchemaValidator, error) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("解析JSON Schema失败: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", doc); err != nil {
+		return nil, fmt.Errorf("加载JSON Schema失败: %w", err)
+	}
+	compiled, err := compiler.Compile("schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("编译JSON Schema失败: %w", err)
+	}
+
+	return &JSONSchemaValidator{schema: compiled}, nil
+}
+
+// Validate 校验配置内容
+func (v *JSONSchemaValidator) Validate(dataId, content string) error {
+	doc, err := decodeContent(dataId, content)
+	if err != nil {
+		return &ValidationError{
+			DataId:     dataId,
+			Violations: []Violation{{Path: "/", Message: err.Error()}},
+		}
+	}
+
+	err = v.schema.Validate(doc)
+	if err == nil {
+		return nil
+	}
+	schemaErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+
+	result := &ValidationError{DataId: dataId}
+	printer := message.NewPrinter(language.English)
+	collectViolations(schemaErr, printer, result)
+	return result
+}
+
+// collectViolations 收集校验错误树中的叶子节点
+func collectViolations(err *jsonschema.ValidationError, printer *message.Printer, result *ValidationError) {
+	if len(err.Causes) == 0 {
+		// 缺少必填属性时按属性分别记录，路径指向缺少的属性而不是其所在的对象
+		if required, ok := err.ErrorKind.(*kind.Required); ok {
+			for _, name := range required.Missing {
+				location := append(append([]string(nil), err.InstanceLocation...), name)
+				result.Violations = append(result.Violations, Violation{
+					Path:    "/" + strings.Join(location, "/"),
+					Message: (&kind.Required{Missing: []string{name}}).LocalizedString(printer),
+				})
+			}
+			return
+		}
+		result.Violations = append(result.Violations, Violation{
+			Path:    "/" + strings.Join(err.InstanceLocation, "/"),
+			Message: err.ErrorKind.LocalizedString(printer),
+		})
+		return
+	}
+	for _, cause := range err.Causes {
+		collectViolations(cause, printer, result)
+	}
+}
+
+// decodeContent 按dataId扩展名将配置内容解析为通用结构
+func decodeContent(dataId, content string) (interface{}, error) {
+	switch configTypeOf(dataId) {
+	case "json":
+		return jsonschema.UnmarshalJSON(strings.NewReader(content))
+	case "toml":
+		var doc map[string]interface{}
+		if err := toml.Unmarshal([]byte(content), &doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	case "properties":
+		props, err := parseProperties(content)
+		if err != nil {
+			return nil, err
+		}
+		return nestProperties(props), nil
+	default:
+		var doc interface{}
+		if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+}
+
+// nestProperties 将 a.b=c 形式的属性展开为嵌套结构
+func nestProperties(props map[string]string) map[string]interface{} {
+	root := make(map[string]interface{})
+	for key, value := range props {
+		node := root
+		parts := strings.Split(key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+	return root
+}
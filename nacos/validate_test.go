@@ -0,0 +1,115 @@
+package nacos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+const dbSchema = `{
+	"type": "object",
+	"required": ["host", "port"],
+	"properties": {
+		"host": {"type": "string"},
+		"port": {"type": "integer", "minimum": 1, "maximum": 65535}
+	}
+}`
+
+func TestJSONSchemaValidator(t *testing.T) {
+	validator, err := NewJSONSchemaValidator(dbSchema)
+	if err != nil {
+		t.Fatalf("NewJSONSchemaValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		dataId    string
+		content   string
+		wantPaths []string
+	}{
+		{name: "valid yaml", dataId: "db.yaml", content: "host: db\nport: 3306\n"},
+		{name: "valid json", dataId: "db.json", content: `{"host": "db", "port": 3306}`},
+		{name: "properties values are strings", dataId: "db.properties", content: "host=db\nport=3306\n", wantPaths: []string{"/port"}},
+		{name: "missing field", dataId: "db.yaml", content: "host: db\n", wantPaths: []string{"/port"}},
+		{name: "missing fields", dataId: "db.yaml", content: "user: root\n", wantPaths: []string{"/host", "/port"}},
+		{name: "missing field and out of range", dataId: "db.yaml", content: "port: 70000\n", wantPaths: []string{"/host", "/port"}},
+		{name: "out of range", dataId: "db.yaml", content: "host: db\nport: 70000\n", wantPaths: []string{"/port"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.dataId, tt.content)
+			if len(tt.wantPaths) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected *ValidationError, got %v", err)
+			}
+			if fmt.Sprint(validationErr.Paths()) != fmt.Sprint(tt.wantPaths) {
+				t.Errorf("Expected paths %v, got %v", tt.wantPaths, validationErr.Paths())
+			}
+		})
+	}
+}
+
+func TestClientValidationGate(t *testing.T) {
+	validator, err := NewJSONSchemaValidator(dbSchema)
+	if err != nil {
+		t.Fatalf("NewJSONSchemaValidator() error = %v", err)
+	}
+
+	sdk := nacostest.NewConfigClient()
+	client := newTestClient(sdk)
+	client.RegisterValidator("db.yaml", "DEFAULT_GROUP", validator)
+	ctx := context.Background()
+
+	// 发布不符合要求的内容被拒绝
+	err = client.PublishConfig(ctx, "db.yaml", "DEFAULT_GROUP", "host: db\nport: 0\n")
+	var nacosErr *NacosError
+	if !errors.As(err, &nacosErr) || nacosErr.Code != ErrConfigInvalid.Code {
+		t.Fatalf("Expected %s error, got %v", ErrConfigInvalid.Code, err)
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Violations) != 1 {
+		t.Errorf("Expected one violation, got %v", err)
+	}
+	if _, ok := sdk.Get("db.yaml", "DEFAULT_GROUP"); ok {
+		t.Error("Expected invalid content not to be published")
+	}
+
+	var pushed []string
+	if err := client.ListenConfig(ctx, "db.yaml", "DEFAULT_GROUP", func(content string) {
+		pushed = append(pushed, content)
+	}); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+
+	// 不符合要求的推送不会交给回调
+	sdk.PublishConfig(vo.ConfigParam{DataId: "db.yaml", Group: "DEFAULT_GROUP", Content: "host: db\n"})
+	if err := client.PublishConfig(ctx, "db.yaml", "DEFAULT_GROUP", "host: db\nport: 3306\n"); err != nil {
+		t.Fatalf("PublishConfig() error = %v", err)
+	}
+	if len(pushed) != 1 || pushed[0] != "host: db\nport: 3306\n" {
+		t.Errorf("Expected only the valid push, got %v", pushed)
+	}
+
+	// 函数校验器
+	client.RegisterValidator("flag.txt", "", ValidatorFunc(func(dataId, content string) error {
+		if content != "on" && content != "off" {
+			return fmt.Errorf("只允许 on/off")
+		}
+		return nil
+	}))
+	if err := client.PublishConfig(ctx, "flag.txt", "", "maybe"); !IsConfigError(err) {
+		t.Errorf("Expected config error, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package nacos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// ViperProviderName viper远程配置提供者名称
+const ViperProviderName = "nacos"
+
+// RegisterViperRemoteProvider 将Nacos注册为viper的远程配置提供者
+//
+// 注册后即可使用viper原生的远程配置接口读取Nacos配置：
+//
+//	viper.AddRemoteProvider("nacos", "DEFAULT_GROUP", "app.yaml")
+//	viper.SetConfigType("yaml")
+//	viper.ReadRemoteConfig()
+//
+// 其中 endpoint 对应分组(group)，path 对应 dataId。
+// viper 的远程配置工厂是全局唯一的，重复注册会覆盖之前的客户端。
+func RegisterViperRemoteProvider(client *NacosClient) error {
+	if client == nil || client.client == nil {
+		return fmt.Errorf("Nacos客户端未初始化")
+	}
+
+	if !slices.Contains(viper.SupportedRemoteProviders, ViperProviderName) {
+		viper.SupportedRemoteProviders = append(viper.SupportedRemoteProviders, ViperProviderName)
+	}
+	viper.RemoteConfig = &viperRemoteConfig{
+		client:   client,
+		watchers: make(map[string]*viperWatcher),
+	}
+	return nil
+}
+
+// viperRemoteConfig 实现viper的远程配置工厂接口
+type viperRemoteConfig struct {
+	client *NacosClient
+
+	mu       sync.Mutex
+	watchers map[string]*viperWatcher
+}
+
+// viperWatcher 保存某个 dataId/group 最近一次推送的内容
+type viperWatcher struct {
+	updates chan string
+}
+
+// Get 读取配置内容
+func (r *viperRemoteConfig) Get(rp viper.RemoteProvider) (io.Reader, error) {
+	content, err := r.client.GetConfig(context.Background(), rp.Path(), rp.Endpoint())
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(content), nil
+}
+
+// Watch 阻塞直到配置发生变化，返回变化后的内容
+// 首次调用时注册监听，两次调用之间的变更只保留最新的一次
+func (r *viperRemoteConfig) Watch(rp viper.RemoteProvider) (io.Reader, error) {
+	w, err := r.watcher(rp)
+	if err != nil {
+		return nil, err
+	}
+	content := <-w.updates
+	return strings.NewReader(content), nil
+}
+
+// WatchChannel 以通道形式持续推送配置变化，向quit写入或关闭quit后停止推送
+func (r *viperRemoteConfig) WatchChannel(rp viper.RemoteProvider) (<-chan *viper.RemoteResponse, chan bool) {
+	responses := make(chan *viper.RemoteResponse, 1)
+	quit := make(chan bool)
+	done := make(chan struct{})
+
+	cancel, err := r.client.addListener(rp.Path(), rp.Endpoint(), func(data string) {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		select {
+		case responses <- &viper.RemoteResponse{Value: []byte(data)}:
+		case <-done:
+		}
+	})
+	if err != nil {
+		responses <- &viper.RemoteResponse{Error: err}
+		return responses, quit
+	}
+
+	go func() {
+		<-quit
+		// 先释放阻塞在发送上的回调，再取消监听，取消时不必等待该回调
+		close(done)
+		cancel()
+	}()
+
+	return responses, quit
+}
+
+// watcher 获取或创建 dataId/group 对应的监听器
+func (r *viperRemoteConfig) watcher(rp viper.RemoteProvider) (*viperWatcher, error) {
+	key := rp.Endpoint() + "@@" + rp.Path()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if w, ok := r.watchers[key]; ok {
+		return w, nil
+	}
+
+	w := &viperWatcher{updates: make(chan string, 1)}
+	_, err := r.client.addListener(rp.Path(), rp.Endpoint(), func(data string) {
+		// 只保留最新内容，旧内容未被消费时直接丢弃
+		for {
+			select {
+			case w.updates <- data:
+				return
+			default:
+			}
+			select {
+			case <-w.updates:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.watchers[key] = w
+	return w, nil
+}
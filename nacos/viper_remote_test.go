@@ -0,0 +1,103 @@
+package nacos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fuyx123/common-package/nacos/nacostest"
+	"github.com/spf13/viper"
+)
+
+// remoteProvider 测试用的 viper.RemoteProvider
+type remoteProvider struct {
+	group  string
+	dataId string
+}
+
+func (p remoteProvider) Provider() string      { return ViperProviderName }
+func (p remoteProvider) Endpoint() string      { return p.group }
+func (p remoteProvider) Path() string          { return p.dataId }
+func (p remoteProvider) SecretKeyring() string { return "" }
+
+func TestViperRemoteProvider(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("app.yaml", "", "port: 8080\n")
+	if err := RegisterViperRemoteProvider(newTestClient(sdk)); err != nil {
+		t.Fatalf("RegisterViperRemoteProvider() error = %v", err)
+	}
+
+	v := viper.New()
+	if err := v.AddRemoteProvider(ViperProviderName, DefaultGroup, "app.yaml"); err != nil {
+		t.Fatalf("AddRemoteProvider() error = %v", err)
+	}
+	v.SetConfigType("yaml")
+	if err := v.ReadRemoteConfig(); err != nil {
+		t.Fatalf("ReadRemoteConfig() error = %v", err)
+	}
+	if got := v.GetInt("port"); got != 8080 {
+		t.Errorf("Expected port 8080, got %d", got)
+	}
+
+	// 预先注册监听，推送保留到 WatchRemoteConfig 读取
+	if _, err := viper.RemoteConfig.(*viperRemoteConfig).watcher(remoteProvider{group: DefaultGroup, dataId: "app.yaml"}); err != nil {
+		t.Fatalf("watcher() error = %v", err)
+	}
+	sdk.Push("app.yaml", "", "port: 9090\n")
+	if err := v.WatchRemoteConfig(); err != nil {
+		t.Fatalf("WatchRemoteConfig() error = %v", err)
+	}
+	if got := v.GetInt("port"); got != 9090 {
+		t.Errorf("Expected port 9090 after watch, got %d", got)
+	}
+}
+
+func TestViperWatchChannelQuit(t *testing.T) {
+	sdk := nacostest.NewConfigClient()
+	sdk.Set("app.yaml", "", "port: 8080\n")
+	// 带占位符的监听在调用回调时持有锁，quit 不能因此死锁
+	client := newTestClient(sdk, WithInterpolation())
+	if err := RegisterViperRemoteProvider(client); err != nil {
+		t.Fatalf("RegisterViperRemoteProvider() error = %v", err)
+	}
+
+	// 先注册的回调先执行，用于得知第二次推送已开始投递
+	delivering := make(chan struct{})
+	if err := client.ListenConfig(context.Background(), "app.yaml", "", func(content string) {
+		if content == "port: 2\n" {
+			close(delivering)
+		}
+	}); err != nil {
+		t.Fatalf("ListenConfig() error = %v", err)
+	}
+
+	publish := func(content string) { sdk.Push("app.yaml", "", content) }
+	responses, quit := viper.RemoteConfig.WatchChannel(remoteProvider{group: DefaultGroup, dataId: "app.yaml"})
+	publish("port: 1\n")
+
+	// 通道已满，第二次推送阻塞在回调中，quit 后应被释放
+	pushed := make(chan struct{})
+	go func() {
+		publish("port: 2\n")
+		close(pushed)
+	}()
+	<-delivering
+
+	close(quit)
+	select {
+	case <-pushed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the blocked push to be released by quit")
+	}
+	publish("port: 3\n")
+
+	resp := <-responses
+	if resp.Error != nil || string(resp.Value) != "port: 1\n" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	select {
+	case resp := <-responses:
+		t.Errorf("Expected no response after quit, got %q", resp.Value)
+	default:
+	}
+}